@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+const (
+	// PlacementReadyCondition reports on the placement of the instance in the placement group it references.
+	PlacementReadyCondition clusterv1.ConditionType = "PlacementReady"
+
+	// PlacementViolatedReason used when the instance is not placed in the placement group it references.
+	PlacementViolatedReason = "PlacementViolated"
+	// PlacementGroupNotStableReason used when the placement group of the instance is not in stable state.
+	PlacementGroupNotStableReason = "PlacementGroupNotStable"
+)
+
+const (
+	// InstanceReadyCondition reports on the lifecycle state of the instance.
+	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
+
+	// InstancePendingReason used when the instance is being provisioned, started or restarted.
+	InstancePendingReason = "InstancePending"
+	// InstanceStoppedReason used when the instance is stopped, paused or being stopped or paused.
+	InstanceStoppedReason = "InstanceStopped"
+	// InstanceFailedReason used when the instance is in failed state.
+	InstanceFailedReason = "InstanceFailed"
+	// InstanceDeletingReason used when the instance is being deleted.
+	InstanceDeletingReason = "InstanceDeleting"
+	// InstanceNotFoundReason used when the instance recorded in the status does not exist anymore.
+	InstanceNotFoundReason = "InstanceNotFound"
+)
+
+const (
+	// InstanceProfileValidCondition reports on whether the instance profile exists in the region.
+	InstanceProfileValidCondition clusterv1.ConditionType = "InstanceProfileValid"
+
+	// InstanceProfileNotFoundReason used when the instance profile does not exist in the region.
+	InstanceProfileNotFoundReason = "InstanceProfileNotFound"
+)
+
+const (
+	// TrustedProfileValidCondition reports on whether the IAM trusted profile linked to the instance exists.
+	TrustedProfileValidCondition clusterv1.ConditionType = "TrustedProfileValid"
+
+	// TrustedProfileNotFoundReason used when the IAM trusted profile does not exist.
+	TrustedProfileNotFoundReason = "TrustedProfileNotFound"
+)
+
+const (
+	// ResourceGroupResolvedCondition reports on the resolution of the resource group referenced by the cluster.
+	ResourceGroupResolvedCondition clusterv1.ConditionType = "ResourceGroupResolved"
+
+	// ResourceGroupNotResolvedReason used when the resource group cannot be resolved, either because it does
+	// not exist or because the IBM Cloud API failed.
+	ResourceGroupNotResolvedReason = "ResourceGroupNotResolved"
+)
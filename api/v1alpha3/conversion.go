@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"k8s.io/apimachinery/pkg/conversion"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+// Convert_v1alpha3_BootstrapStorage_To_v1alpha4_BootstrapStorage converts the bootstrap storage of a cluster
+// to the storage consumed by the PowerVS and VPC machine scopes.
+func Convert_v1alpha3_BootstrapStorage_To_v1alpha4_BootstrapStorage(in *BootstrapStorage, out *v1alpha4.BootstrapStorage, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Region = in.Region
+	out.Bucket = in.Bucket
+	out.URLExpiry = in.URLExpiry
+	return nil
+}
+
+// Convert_v1alpha3_UserDataSecretReference_To_v1alpha4_UserDataSecretReference converts a reference to a Secret
+// holding a cloud-init part.
+func Convert_v1alpha3_UserDataSecretReference_To_v1alpha4_UserDataSecretReference(in *UserDataSecretReference, out *v1alpha4.UserDataSecretReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Key = in.Key
+	out.ContentType = in.ContentType
+	return nil
+}
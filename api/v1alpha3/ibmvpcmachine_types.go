@@ -19,6 +19,7 @@ package v1alpha3
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -39,8 +40,13 @@ type IBMVPCMachineSpec struct {
 
 	// Image is the id of OS image which would be install on the instance.
 	// Example: r134-ed3f775f-ad7e-4e37-ae62-7199b4988b00
-	// TODO: allow user to specify a image name is much reasonable. Example: ibm-ubuntu-18-04-1-minimal-amd64-2
-	Image string `json:"image"`
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ImageRef is the reference to the OS image by id or name, used when Image is not set.
+	// Example: {name: ibm-ubuntu-18-04-1-minimal-amd64-2}
+	// +optional
+	ImageRef *IBMVPCResourceReference `json:"imageRef,omitempty"`
 
 	// Zone is the place where the instance should be created. Example: us-south-3
 	// TODO: Actually zone is transparent to user. The field user can access is location. Example: Dallas 2
@@ -59,6 +65,55 @@ type IBMVPCMachineSpec struct {
 
 	// SSHKeys is the SSH pub keys that will be used to access VM
 	SSHKeys []*string `json:"sshKeys,omitempty"`
+
+	// SSHKeyRefs are the references to the SSH pub keys by id or name, added to SSHKeys.
+	// +optional
+	SSHKeyRefs []*IBMVPCResourceReference `json:"sshKeyRefs,omitempty"`
+
+	// DataVolumes are the additional block volumes created and attached along with the instance.
+	// +optional
+	DataVolumes []VPCVolume `json:"dataVolumes,omitempty"`
+
+	// PlacementGroup is the reference to the placement group the instance is placed in.
+	// Control plane machines default to the placement group created for the cluster control plane.
+	// +optional
+	PlacementGroup *IBMVPCResourceReference `json:"placementGroup,omitempty"`
+
+	// DedicatedHost is the reference to the dedicated host the instance is placed on.
+	// Only one of PlacementGroup, DedicatedHost or DedicatedHostGroup may be specified.
+	// +optional
+	DedicatedHost *IBMVPCResourceReference `json:"dedicatedHost,omitempty"`
+
+	// DedicatedHostGroup is the reference to the dedicated host group the instance is placed in.
+	// Only one of PlacementGroup, DedicatedHost or DedicatedHostGroup may be specified.
+	// +optional
+	DedicatedHostGroup *IBMVPCResourceReference `json:"dedicatedHostGroup,omitempty"`
+
+	// Tags are the user tags attached to the instance and its volumes in addition to the cluster tags.
+	// Tags removed from the list are not detached from the resources.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// AccessTags are the access management tags attached to the instance and its volumes in addition
+	// to the cluster access tags. The tags must already exist in the account. Tags removed from the list
+	// are not detached from the resources.
+	// +optional
+	AccessTags []string `json:"accessTags,omitempty"`
+
+	// TrustedProfile is the ID of the IAM trusted profile linked to the instance, workloads on the instance
+	// get tokens for the profile from the instance metadata service. Defaults to the trusted profile of the cluster.
+	// +optional
+	TrustedProfile string `json:"trustedProfile,omitempty"`
+
+	// MetadataService enables the instance metadata service, it is always enabled when a trusted profile is linked.
+	// +optional
+	MetadataService bool `json:"metadataService,omitempty"`
+
+	// AdditionalUserData are references to Secrets holding cloud-init parts merged with the bootstrap data
+	// into a multi-part MIME user data. The parts are ordered as listed, before the bootstrap data.
+	// Only bootstrap data in cloud-config format can be merged.
+	// +optional
+	AdditionalUserData []UserDataSecretReference `json:"additionalUserData,omitempty"`
 }
 
 // IBMVPCMachineStatus defines the observed state of IBMVPCMachine
@@ -76,6 +131,44 @@ type IBMVPCMachineStatus struct {
 	// InstanceStatus is the status of the GCP instance for this machine.
 	// +optional
 	InstanceStatus string `json:"instanceState,omitempty"`
+
+	// ImageID is the id of the image resolved from the spec.
+	// +optional
+	ImageID string `json:"imageID,omitempty"`
+
+	// SSHKeyIDs are the ids of the SSH keys resolved from the spec.
+	// +optional
+	SSHKeyIDs []string `json:"sshKeyIDs,omitempty"`
+
+	// SubnetID is the id of the subnet of the primary network interface resolved from the spec.
+	// +optional
+	SubnetID string `json:"subnetID,omitempty"`
+
+	// DataVolumes are the data volumes attached to the instance.
+	// +optional
+	DataVolumes []VPCVolumeStatus `json:"dataVolumes,omitempty"`
+
+	// PlacementGroupID is the id of the placement group the instance is placed in.
+	// +optional
+	PlacementGroupID string `json:"placementGroupID,omitempty"`
+
+	// DedicatedHostID is the id of the dedicated host the instance is running on.
+	// +optional
+	DedicatedHostID string `json:"dedicatedHostID,omitempty"`
+
+	// BootstrapDataObject is the key of the Cloud Object Storage object holding the bootstrap data of the instance,
+	// the object is deleted once the node of the machine joined the cluster.
+	// +optional
+	BootstrapDataObject string `json:"bootstrapDataObject,omitempty"`
+
+	// TagsHash is the hash of the tags last attached to the instance and its volumes, they are attached again
+	// once the tags or the tagged resources change.
+	// +optional
+	TagsHash string `json:"tagsHash,omitempty"`
+
+	// Conditions defines current service state of the IBMVPCMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -92,6 +185,16 @@ type IBMVPCMachine struct {
 	Status IBMVPCMachineStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the observations of the operational state of the IBMVPCMachine resource.
+func (r *IBMVPCMachine) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMVPCMachine to the predescribed clusterv1.Conditions.
+func (r *IBMVPCMachine) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // IBMVPCMachineList contains a list of IBMVPCMachine
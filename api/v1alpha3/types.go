@@ -16,10 +16,85 @@ limitations under the License.
 
 package v1alpha3
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IBMVPCResourceReference is a reference to a specific VPC resource by ID or Name
+// Exactly one of ID or Name must be specified, the API server rejects a reference
+// with none or both of them.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type IBMVPCResourceReference struct {
+	// ID of resource
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// Name of resource
+	// +optional
+	Name *string `json:"name,omitempty"`
+}
+
+// VPCPlacementGroup describes a placement group created by the provider.
+type VPCPlacementGroup struct {
+	// Strategy is the placement strategy of the group, host_spread places the instances on different hosts
+	// and power_spread places them on hosts with different power sources and network connections.
+	// +kubebuilder:validation:Enum=host_spread;power_spread
+	Strategy string `json:"strategy"`
+}
+
 // NetworkInterface holds the network interface information like subnet id.
 type NetworkInterface struct {
 	// Subnet ID of the network interface
 	Subnet string `json:"subnet,omitempty"`
+
+	// SubnetRef is the reference to the subnet by id or name, used when Subnet is not set.
+	// +optional
+	SubnetRef *IBMVPCResourceReference `json:"subnetRef,omitempty"`
+}
+
+// VPCVolume defines a data volume which is created and attached along with a VPC instance.
+type VPCVolume struct {
+	// Name of the volume, it will be prefixed with the name of the machine.
+	// Defaults to data-<index of the volume>
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// SizeGiB is the capacity of the volume in GiB.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=16000
+	SizeGiB int64 `json:"sizeGiB"`
+
+	// Profile is the name of the volume profile. Example: general-purpose, 5iops-tier, 10iops-tier or custom
+	// +kubebuilder:default=general-purpose
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// Iops is the maximum I/O operations per second, only applicable to the custom profile.
+	// +optional
+	Iops int64 `json:"iops,omitempty"`
+
+	// EncryptionKeyCRN is the CRN of the root key used to encrypt the volume.
+	// If unspecified the volume is encrypted with a provider managed key.
+	// +optional
+	EncryptionKeyCRN string `json:"encryptionKeyCRN,omitempty"`
+
+	// DeleteOnInstanceDelete indicates whether the volume is deleted along with the instance.
+	// +kubebuilder:default=true
+	// +optional
+	DeleteOnInstanceDelete *bool `json:"deleteOnInstanceDelete,omitempty"`
+}
+
+// VPCVolumeStatus describes a data volume attached to a VPC instance.
+type VPCVolumeStatus struct {
+	// Name of the volume
+	Name string `json:"name"`
+
+	// ID of the volume
+	ID string `json:"id"`
+
+	// AttachmentID is the id of the volume attachment on the instance
+	AttachmentID string `json:"attachmentID,omitempty"`
 }
 
 // Subnet describes a subnet
@@ -35,3 +110,51 @@ type APIEndpoint struct {
 	Address *string `json:"address"`
 	FIPID   *string `json:"floatingIPID"`
 }
+
+// BootstrapStorage configures the delivery of the bootstrap data of the machines through a Cloud Object Storage bucket.
+// The instances get a small user data downloading the bootstrap data with a presigned URL, the HMAC credentials
+// of the bucket are read from the COS_ACCESS_KEY_ID and COS_SECRET_ACCESS_KEY credentials.
+type BootstrapStorage struct {
+	// Endpoint is the S3 compatible endpoint of the Cloud Object Storage instance.
+	// Example: https://s3.us-south.cloud-object-storage.appdomain.cloud
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region of the bucket. Example: us-south
+	Region string `json:"region"`
+
+	// Bucket is the name of the bucket, it is created when it does not exist. A bucket created by the controller is
+	// deleted with the cluster. Defaults to <cluster name>-bootstrap.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// URLExpiry is how long the presigned URL of the bootstrap data is valid, it must cover the provisioning
+	// and first boot of the instances. The object is deleted once the node of the machine joined the cluster.
+	// Defaults to 30m, the maximum is 168h.
+	// +optional
+	URLExpiry *metav1.Duration `json:"urlExpiry,omitempty"`
+}
+
+// BootstrapBucketStatus describes the bucket holding the bootstrap data of the machines.
+type BootstrapBucketStatus struct {
+	// Name of the bucket.
+	Name string `json:"name"`
+
+	// ControllerCreated is true when the bucket was created by the controller, it is then deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+}
+
+// UserDataSecretReference references a Secret holding a cloud-init part merged into the user data of an instance.
+type UserDataSecretReference struct {
+	// Name of the Secret in the namespace of the machine.
+	Name string `json:"name"`
+
+	// Key of the part in the Secret. Defaults to value.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// ContentType is the MIME type of the part. When empty it is detected from the first line of the part,
+	// for example text/cloud-config for #cloud-config and text/x-shellscript for #!.
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
@@ -22,7 +22,9 @@ package v1alpha3
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -50,12 +52,47 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapBucketStatus) DeepCopyInto(out *BootstrapBucketStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapBucketStatus.
+func (in *BootstrapBucketStatus) DeepCopy() *BootstrapBucketStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapBucketStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStorage) DeepCopyInto(out *BootstrapStorage) {
+	*out = *in
+	if in.URLExpiry != nil {
+		in, out := &in.URLExpiry, &out.URLExpiry
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStorage.
+func (in *BootstrapStorage) DeepCopy() *BootstrapStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMVPCCluster) DeepCopyInto(out *IBMVPCCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -112,7 +149,32 @@ func (in *IBMVPCClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMVPCClusterSpec) DeepCopyInto(out *IBMVPCClusterSpec) {
 	*out = *in
+	if in.ResourceGroupRef != nil {
+		in, out := &in.ResourceGroupRef, &out.ResourceGroupRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlanePlacementGroup != nil {
+		in, out := &in.ControlPlanePlacementGroup, &out.ControlPlanePlacementGroup
+		*out = new(VPCPlacementGroup)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootstrapStorage != nil {
+		in, out := &in.BootstrapStorage, &out.BootstrapStorage
+		*out = new(BootstrapStorage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCClusterSpec.
@@ -131,6 +193,18 @@ func (in *IBMVPCClusterStatus) DeepCopyInto(out *IBMVPCClusterStatus) {
 	out.VPC = in.VPC
 	in.Subnet.DeepCopyInto(&out.Subnet)
 	in.APIEndpoint.DeepCopyInto(&out.APIEndpoint)
+	if in.BootstrapBucket != nil {
+		in, out := &in.BootstrapBucket, &out.BootstrapBucket
+		*out = new(BootstrapBucketStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCClusterStatus.
@@ -205,12 +279,17 @@ func (in *IBMVPCMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMVPCMachineSpec) DeepCopyInto(out *IBMVPCMachineSpec) {
 	*out = *in
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProviderID != nil {
 		in, out := &in.ProviderID, &out.ProviderID
 		*out = new(string)
 		**out = **in
 	}
-	out.PrimaryNetworkInterface = in.PrimaryNetworkInterface
+	in.PrimaryNetworkInterface.DeepCopyInto(&out.PrimaryNetworkInterface)
 	if in.SSHKeys != nil {
 		in, out := &in.SSHKeys, &out.SSHKeys
 		*out = make([]*string, len(*in))
@@ -222,6 +301,54 @@ func (in *IBMVPCMachineSpec) DeepCopyInto(out *IBMVPCMachineSpec) {
 			}
 		}
 	}
+	if in.SSHKeyRefs != nil {
+		in, out := &in.SSHKeyRefs, &out.SSHKeyRefs
+		*out = make([]*IBMVPCResourceReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(IBMVPCResourceReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.DataVolumes != nil {
+		in, out := &in.DataVolumes, &out.DataVolumes
+		*out = make([]VPCVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DedicatedHost != nil {
+		in, out := &in.DedicatedHost, &out.DedicatedHost
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DedicatedHostGroup != nil {
+		in, out := &in.DedicatedHostGroup, &out.DedicatedHostGroup
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalUserData != nil {
+		in, out := &in.AdditionalUserData, &out.AdditionalUserData
+		*out = make([]UserDataSecretReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineSpec.
@@ -242,6 +369,23 @@ func (in *IBMVPCMachineStatus) DeepCopyInto(out *IBMVPCMachineStatus) {
 		*out = make([]v1.NodeAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.SSHKeyIDs != nil {
+		in, out := &in.SSHKeyIDs, &out.SSHKeyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataVolumes != nil {
+		in, out := &in.DataVolumes, &out.DataVolumes
+		*out = make([]VPCVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineStatus.
@@ -260,6 +404,7 @@ func (in *IBMVPCMachineTemplate) DeepCopyInto(out *IBMVPCMachineTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineTemplate.
@@ -344,9 +489,68 @@ func (in *IBMVPCMachineTemplateSpec) DeepCopy() *IBMVPCMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachineTemplateStatus) DeepCopyInto(out *IBMVPCMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineTemplateStatus.
+func (in *IBMVPCMachineTemplateStatus) DeepCopy() *IBMVPCMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCResourceReference) DeepCopyInto(out *IBMVPCResourceReference) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCResourceReference.
+func (in *IBMVPCResourceReference) DeepCopy() *IBMVPCResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
 	*out = *in
+	if in.SubnetRef != nil {
+		in, out := &in.SubnetRef, &out.SubnetRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
@@ -394,6 +598,21 @@ func (in *Subnet) DeepCopy() *Subnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSecretReference) DeepCopyInto(out *UserDataSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSecretReference.
+func (in *UserDataSecretReference) DeepCopy() *UserDataSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPC) DeepCopyInto(out *VPC) {
 	*out = *in
@@ -408,3 +627,53 @@ func (in *VPC) DeepCopy() *VPC {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCPlacementGroup) DeepCopyInto(out *VPCPlacementGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCPlacementGroup.
+func (in *VPCPlacementGroup) DeepCopy() *VPCPlacementGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCPlacementGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCVolume) DeepCopyInto(out *VPCVolume) {
+	*out = *in
+	if in.DeleteOnInstanceDelete != nil {
+		in, out := &in.DeleteOnInstanceDelete, &out.DeleteOnInstanceDelete
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCVolume.
+func (in *VPCVolume) DeepCopy() *VPCVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCVolumeStatus) DeepCopyInto(out *VPCVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCVolumeStatus.
+func (in *VPCVolumeStatus) DeepCopy() *VPCVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
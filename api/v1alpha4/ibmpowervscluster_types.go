@@ -35,15 +35,58 @@ type IBMPowerVSClusterSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// ServiceInstanceID is the id of the power cloud instance where the vsi instance will get deployed
-	ServiceInstanceID string `json:"serviceInstanceID"`
+	// ServiceInstanceID is the id of the power cloud instance where the vsi instance will get deployed.
+	// It must be omitted when ServiceInstance is set.
+	// +optional
+	ServiceInstanceID string `json:"serviceInstanceID,omitempty"`
+
+	// ServiceInstance configures the power cloud instance created for the cluster when ServiceInstanceID is not set,
+	// the service instance is owned by the cluster and deleted with it. It is not created when a service instance of
+	// the same name exists in the resource group, an existing service instance is referenced with ServiceInstanceID.
+	// +optional
+	ServiceInstance *PowerVSServiceInstance `json:"serviceInstance,omitempty"`
 
 	// Network is the reference to the Network to use for this cluster.
-	Network IBMPowerVSResourceReference `json:"network"`
+	// It must be omitted when PrivateNetwork is set.
+	// +optional
+	Network IBMPowerVSResourceReference `json:"network,omitempty"`
+
+	// PrivateNetwork configures the private network created in the service instance for the cluster,
+	// the network is owned by the cluster and deleted with it.
+	// +optional
+	PrivateNetwork *PowerVSPrivateNetwork `json:"privateNetwork,omitempty"`
 
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// It is populated from the reserved virtual IP when ControlPlaneVIP is set.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// ControlPlaneVIP reserves the virtual IP of the control plane as a port of the cluster network, and of a public
+	// network when it is public, so the address is not leased to an instance. The ports are owned by the cluster
+	// and deleted with it.
+	// +optional
+	ControlPlaneVIP *PowerVSControlPlaneVIP `json:"controlPlaneVIP,omitempty"`
+
+	// PlacementGroups are the server placement groups created in the service instance for the machines of the cluster,
+	// a group is deleted once it has no members, with the cluster or after it is removed from the list.
+	// +optional
+	PlacementGroups []PowerVSPlacementGroup `json:"placementGroups,omitempty"`
+
+	// SharedProcessorPool is the shared processor pool created in the service instance for the machines of the cluster,
+	// its reserved cores are resized with the spec and it is deleted with the cluster once it has no instances.
+	// +optional
+	SharedProcessorPool *PowerVSSharedProcessorPool `json:"sharedProcessorPool,omitempty"`
+
+	// SSHKeySecret is the reference to a Secret holding an SSH public key, the key is registered as a key pair
+	// named <namespace>-<cluster name>-sshkey which the machines use when they do not set an SSH key.
+	// An existing key pair of that name holding the same key is used without being updated or deleted.
+	// +optional
+	SSHKeySecret *SSHKeySecretReference `json:"sshKeySecret,omitempty"`
+
+	// BootstrapStorage delivers the bootstrap data of the machines through a Cloud Object Storage bucket
+	// instead of the instance user data, which is limited in size.
+	// +optional
+	BootstrapStorage *BootstrapStorage `json:"bootstrapStorage,omitempty"`
 }
 
 // IBMPowerVSClusterStatus defines the observed state of IBMPowerVSCluster
@@ -51,6 +94,246 @@ type IBMPowerVSClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Ready bool `json:"ready"`
+
+	// ServiceInstance is the power cloud instance created for the cluster.
+	// +optional
+	ServiceInstance *PowerVSServiceInstanceStatus `json:"serviceInstance,omitempty"`
+
+	// Network is the network of the cluster.
+	// +optional
+	Network *PowerVSNetworkStatus `json:"network,omitempty"`
+
+	// ControlPlaneVIP describes the ports reserved for the virtual IP of the control plane.
+	// +optional
+	ControlPlaneVIP *PowerVSControlPlaneVIPStatus `json:"controlPlaneVIP,omitempty"`
+
+	// PlacementGroups are the server placement groups of the cluster.
+	// +optional
+	PlacementGroups []PowerVSPlacementGroupStatus `json:"placementGroups,omitempty"`
+
+	// SharedProcessorPool is the shared processor pool of the cluster.
+	// +optional
+	SharedProcessorPool *PowerVSSharedProcessorPoolStatus `json:"sharedProcessorPool,omitempty"`
+
+	// SSHKey is the key pair registered from SSHKeySecret.
+	// +optional
+	SSHKey *PowerVSSSHKeyStatus `json:"sshKey,omitempty"`
+
+	// BootstrapBucket is the bucket of the bootstrap data when it is delivered through Cloud Object Storage.
+	// +optional
+	BootstrapBucket *BootstrapBucketStatus `json:"bootstrapBucket,omitempty"`
+}
+
+// PowerVSSSHKeyStatus describes the key pair registered for a cluster.
+type PowerVSSSHKeyStatus struct {
+	// Name of the key pair.
+	Name string `json:"name"`
+
+	// ControllerCreated is true when the key pair was created by the controller, it is then updated with the Secret
+	// and deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+}
+
+// SSHKeySecretReference is a reference to a Secret holding an SSH public key.
+type SSHKeySecretReference struct {
+	// Name of the Secret in the namespace of the cluster.
+	Name string `json:"name"`
+
+	// Key of the public key in the Secret.
+	// +kubebuilder:default=ssh-publickey
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// PowerVSPlacementGroup describes a server placement group.
+type PowerVSPlacementGroup struct {
+	// Name of the placement group, machines reference the group by this name.
+	Name string `json:"name"`
+
+	// Policy of the placement group, affinity places the members on the same server and anti-affinity
+	// on different servers.
+	// +kubebuilder:validation:Enum=affinity;anti-affinity
+	Policy string `json:"policy"`
+}
+
+// PowerVSPlacementGroupStatus describes a server placement group of a cluster.
+type PowerVSPlacementGroupStatus struct {
+	// Name of the placement group.
+	Name string `json:"name"`
+
+	// ID of the placement group.
+	ID string `json:"id"`
+
+	// ControllerCreated is true when the placement group was created by the controller, it is then deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+}
+
+// PowerVSSharedProcessorPool describes a shared processor pool.
+type PowerVSSharedProcessorPool struct {
+	// Name of the shared processor pool, machines reference the pool by this name.
+	Name string `json:"name"`
+
+	// HostGroup is the host group of the host the pool is created on, e.g: s922, e980.
+	HostGroup string `json:"hostGroup"`
+
+	// ReservedCores is the number of cores reserved for the instances of the pool.
+	// +kubebuilder:validation:Minimum=1
+	ReservedCores int64 `json:"reservedCores"`
+}
+
+// PowerVSSharedProcessorPoolStatus describes the shared processor pool of a cluster.
+type PowerVSSharedProcessorPoolStatus struct {
+	// Name of the shared processor pool.
+	Name string `json:"name"`
+
+	// ID of the shared processor pool.
+	ID string `json:"id"`
+
+	// ReservedCores is the number of cores reserved for the pool.
+	// +optional
+	ReservedCores int64 `json:"reservedCores,omitempty"`
+
+	// State of the shared processor pool.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// ControllerCreated is true when the pool was created by the controller, it is then resized with the spec
+	// and deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+}
+
+// PowerVSControlPlaneVIP describes the virtual IP reserved for the control plane of a cluster.
+type PowerVSControlPlaneVIP struct {
+	// IPAddress is the internal address to reserve, an address of the network is chosen when omitted.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// Public reserves a second port on a public network and uses its external IP as the control plane endpoint,
+	// the internal address is used otherwise. The control plane machines must be attached to the public network.
+	// +optional
+	Public bool `json:"public,omitempty"`
+
+	// PublicNetwork is the public network the port of a public virtual IP is reserved on.
+	// Defaults to the public network of the service instance, which must be unique.
+	// +optional
+	PublicNetwork *IBMPowerVSResourceReference `json:"publicNetwork,omitempty"`
+
+	// Port of the API server.
+	// +kubebuilder:default=6443
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// PowerVSControlPlaneVIPStatus describes the ports reserved for the virtual IP of the control plane.
+type PowerVSControlPlaneVIPStatus struct {
+	// PortID is the id of the port reserved on the cluster network.
+	PortID string `json:"portID"`
+
+	// IPAddress is the internal address of the port.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// PublicNetworkID is the id of the public network of the public port.
+	// +optional
+	PublicNetworkID string `json:"publicNetworkID,omitempty"`
+
+	// PublicPortID is the id of the port reserved on the public network when the virtual IP is public.
+	// +optional
+	PublicPortID string `json:"publicPortID,omitempty"`
+
+	// ExternalIP is the external address of the public port.
+	// +optional
+	ExternalIP string `json:"externalIP,omitempty"`
+}
+
+// PowerVSServiceInstance describes the power cloud instance created for a cluster.
+type PowerVSServiceInstance struct {
+	// Name of the service instance. Defaults to <cluster name>-serviceinstance.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Zone where the service instance is created. Example: lon04
+	Zone string `json:"zone"`
+
+	// ResourceGroup is the reference to the resource group of the service instance.
+	ResourceGroup IBMPowerVSResourceReference `json:"resourceGroup"`
+}
+
+// PowerVSServiceInstanceStatus describes the power cloud instance created for a cluster.
+type PowerVSServiceInstanceStatus struct {
+	// ID is the GUID of the service instance.
+	ID string `json:"id"`
+
+	// State of the service instance.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// ControllerCreated is true when the service instance was created by the controller, it is then deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+}
+
+// PowerVSPrivateNetwork describes the private network created for a cluster.
+type PowerVSPrivateNetwork struct {
+	// Name of the network. Defaults to <cluster name>-network.
+	// The name of a network served by DHCP is chosen by the service.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// DHCP creates the network with a DHCP server of the service instance, which then chooses
+	// the CIDR and the DNS servers of the network.
+	// +optional
+	DHCP bool `json:"dhcp,omitempty"`
+
+	// CIDR of the network with a static IP range. Required unless DHCP is set. Example: 192.168.0.0/24
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// Gateway of the network. Defaults to the first address of the CIDR.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// StartIP is the first address of the static IP range. Defaults to the address following the gateway.
+	// +optional
+	StartIP string `json:"startIP,omitempty"`
+
+	// EndIP is the last address of the static IP range. Defaults to the last usable address of the CIDR.
+	// +optional
+	EndIP string `json:"endIP,omitempty"`
+
+	// DNSServers are the DNS servers of the network with a static IP range. They can't be specified with DHCP,
+	// the DHCP server of the service instance is created without options and advertises its own DNS servers.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// Jumbo enables jumbo frames on the network.
+	// +optional
+	Jumbo bool `json:"jumbo,omitempty"`
+}
+
+// PowerVSNetworkStatus describes the network of a cluster.
+type PowerVSNetworkStatus struct {
+	// ID of the network.
+	ID string `json:"id"`
+
+	// Name of the network.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CIDR of the network.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// DHCPServerID is the id of the DHCP server serving the network.
+	// +optional
+	DHCPServerID string `json:"dhcpServerID,omitempty"`
+
+	// ControllerCreated is true when the network was created by the controller, it is then deleted with the cluster.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
 }
 
 // +kubebuilder:subresource:status
@@ -74,6 +357,18 @@ type IBMPowerVSClusterList struct {
 	Items           []IBMPowerVSCluster `json:"items"`
 }
 
+// GetServiceInstanceID returns the id of the power cloud instance of the cluster, either referenced in the spec
+// or created for the cluster. It is empty until the service instance is created.
+func (r *IBMPowerVSCluster) GetServiceInstanceID() string {
+	if r.Spec.ServiceInstanceID != "" {
+		return r.Spec.ServiceInstanceID
+	}
+	if r.Status.ServiceInstance != nil {
+		return r.Status.ServiceInstance.ID
+	}
+	return ""
+}
+
 func init() {
 	SchemeBuilder.Register(&IBMPowerVSCluster{}, &IBMPowerVSClusterList{})
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+const (
+	// IBMPowerVSImageFinalizer allows IBMPowerVSImageReconciler to clean up resources associated with IBMPowerVSImage before
+	// removing it from the apiserver.
+	IBMPowerVSImageFinalizer = "ibmpowervsimage.infrastructure.cluster.x-k8s.io"
+)
+
+// IBMPowerVSImageSpec defines the desired state of IBMPowerVSImage
+type IBMPowerVSImageSpec struct {
+	// ClusterName is the name of the Cluster the image belongs to, the image is imported in the
+	// service instance of its IBMPowerVSCluster when ServiceInstanceID is not set.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// ServiceInstanceID is the id of the power cloud instance where the image is imported.
+	// +optional
+	ServiceInstanceID string `json:"serviceInstanceID,omitempty"`
+
+	// Bucket is the Cloud Object Storage bucket holding the image.
+	Bucket string `json:"bucket"`
+
+	// Object is the name of the image file in the bucket, an OVA file or a gzip compressed OVA file.
+	Object string `json:"object"`
+
+	// Region of the Cloud Object Storage bucket. Example: us-south
+	Region string `json:"region"`
+
+	// BucketAccess is the access of the bucket, the HMAC keys of a private bucket are read from
+	// COS_ACCESS_KEY_ID and COS_SECRET_ACCESS_KEY.
+	// +kubebuilder:validation:Enum=public;private
+	// +kubebuilder:default=public
+	// +optional
+	BucketAccess string `json:"bucketAccess,omitempty"`
+
+	// StorageType of the volumes of the image.
+	// +kubebuilder:validation:Enum=tier1;tier3
+	// +kubebuilder:default=tier1
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
+
+	// OSType of the image.
+	// +kubebuilder:validation:Enum=aix;ibmi;rhel;sles
+	// +optional
+	OSType string `json:"osType,omitempty"`
+}
+
+// IBMPowerVSImageStatus defines the observed state of IBMPowerVSImage
+type IBMPowerVSImageStatus struct {
+	// Ready is true when the image is active and can be used by machines.
+	Ready bool `json:"ready"`
+
+	// ImageID is the id of the imported image.
+	// +optional
+	ImageID string `json:"imageID,omitempty"`
+
+	// ImageState is the state of the imported image.
+	// +optional
+	ImageState string `json:"imageState,omitempty"`
+
+	// JobID is the id of the import job.
+	// +optional
+	JobID string `json:"jobID,omitempty"`
+
+	// ControllerCreated is true when the image was imported by the controller, it is then deleted with the IBMPowerVSImage.
+	// +optional
+	ControllerCreated bool `json:"controllerCreated,omitempty"`
+
+	// Conditions defines current service state of the IBMPowerVSImage.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Image is ready for IBM PowerVS instances"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.imageState",description="PowerVS image state"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.imageID",description="PowerVS image id"
+
+// IBMPowerVSImage is the Schema for the ibmpowervsimages API
+type IBMPowerVSImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IBMPowerVSImageSpec   `json:"spec,omitempty"`
+	Status IBMPowerVSImageStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the observations of the operational state of the IBMPowerVSImage resource.
+func (r *IBMPowerVSImage) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMPowerVSImage to the predescribed clusterv1.Conditions.
+func (r *IBMPowerVSImage) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// IBMPowerVSImageList contains a list of IBMPowerVSImage
+type IBMPowerVSImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IBMPowerVSImage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IBMPowerVSImage{}, &IBMPowerVSImageList{})
+}
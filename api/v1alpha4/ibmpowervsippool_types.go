@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IBMPowerVSIPPoolSpec defines the desired state of IBMPowerVSIPPool
+type IBMPowerVSIPPoolSpec struct {
+	// Network is the reference to the PowerVS network the addresses of the pool belong to.
+	Network IBMPowerVSResourceReference `json:"network"`
+
+	// Ranges are the ranges of IPv4 addresses of the pool.
+	// +kubebuilder:validation:MinItems=1
+	Ranges []IPRange `json:"ranges"`
+}
+
+// IPRange is an inclusive range of IPv4 addresses.
+type IPRange struct {
+	// Start is the first address of the range.
+	Start string `json:"start"`
+
+	// End is the last address of the range.
+	End string `json:"end"`
+}
+
+// IBMPowerVSIPPoolStatus defines the observed state of IBMPowerVSIPPool
+type IBMPowerVSIPPoolStatus struct {
+	// Allocations are the addresses claimed by the machines.
+	// +optional
+	Allocations []IPAllocation `json:"allocations,omitempty"`
+}
+
+// IPAllocation is an address of a pool claimed by a machine for one of its networks.
+type IPAllocation struct {
+	// Address is the claimed address.
+	Address string `json:"address"`
+
+	// Machine is the name of the IBMPowerVSMachine which claimed the address.
+	Machine string `json:"machine"`
+
+	// Network is the ID of the network the machine is attached to with the address.
+	Network string `json:"network"`
+}
+
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Network",type="string",JSONPath=".spec.network.name",description="PowerVS network of the addresses"
+
+// IBMPowerVSIPPool is the Schema for the ibmpowervsippools API
+type IBMPowerVSIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IBMPowerVSIPPoolSpec   `json:"spec,omitempty"`
+	Status IBMPowerVSIPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IBMPowerVSIPPoolList contains a list of IBMPowerVSIPPool
+type IBMPowerVSIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IBMPowerVSIPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IBMPowerVSIPPool{}, &IBMPowerVSIPPoolList{})
+}
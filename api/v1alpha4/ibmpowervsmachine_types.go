@@ -19,6 +19,7 @@ package v1alpha4
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -35,14 +36,25 @@ type IBMPowerVSMachineSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// ServiceInstanceID is the id of the power cloud instance where the vsi instance will get deployed
-	ServiceInstanceID string `json:"serviceInstanceID"`
+	// ServiceInstanceID is the id of the power cloud instance where the vsi instance will get deployed.
+	// Defaults to the service instance of the cluster.
+	// +optional
+	ServiceInstanceID string `json:"serviceInstanceID,omitempty"`
 
-	// SSHKey is the name of the SSH key pair provided to the vsi for authenticating users
+	// SSHKey is the name of the SSH key pair provided to the vsi for authenticating users.
+	// Defaults to the key pair registered from the SSH key Secret of the cluster.
+	// +optional
 	SSHKey string `json:"sshKey,omitempty"`
 
 	// Image is the reference to the Image from which to create the machine instance.
-	Image IBMPowerVSResourceReference `json:"image"`
+	// It must be omitted when ImageRef is set.
+	// +optional
+	Image IBMPowerVSResourceReference `json:"image,omitempty"`
+
+	// ImageRef is the reference to the IBMPowerVSImage from which to create the machine instance,
+	// the instance is created once the image is ready.
+	// +optional
+	ImageRef *v1.LocalObjectReference `json:"imageRef,omitempty"`
 
 	// SysType is the System type used to host the vsi
 	SysType string `json:"sysType"`
@@ -56,12 +68,68 @@ type IBMPowerVSMachineSpec struct {
 	// Memory is Amount of memory allocated (in GB)
 	Memory string `json:"memory"`
 
+	// ResizeInPlace resizes the instance when ProcType, Processors or Memory are changed after its creation,
+	// otherwise the changes are ignored. The processor type can only be changed while the instance is shut off.
+	// +optional
+	ResizeInPlace bool `json:"resizeInPlace,omitempty"`
+
+	// SharedProcessorPool is the reference to the shared processor pool of the instance,
+	// either the shared processor pool of the cluster or an existing one. It requires a shared or capped ProcType.
+	// +optional
+	SharedProcessorPool *IBMPowerVSResourceReference `json:"sharedProcessorPool,omitempty"`
+
+	// StorageType is the storage tier of the boot volume, e.g: tier1, tier3.
+	// Defaults to the storage type of the image.
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
+
+	// StoragePool is the storage pool of the boot volume, it must hold the StorageType.
+	// It cannot be set with StorageAffinity.
+	// +optional
+	StoragePool string `json:"storagePool,omitempty"`
+
+	// StorageAffinity selects the storage pool of the boot volume from the one of an existing volume or instance.
+	// +optional
+	StorageAffinity *PowerVSStorageAffinity `json:"storageAffinity,omitempty"`
+
+	// PlacementGroup is the reference to the server placement group of the instance,
+	// either one of the placement groups of the cluster or an existing one.
+	// +optional
+	PlacementGroup *IBMPowerVSResourceReference `json:"placementGroup,omitempty"`
+
+	// Volumes are the data volumes created and attached to the instance at provisioning.
+	// +optional
+	Volumes []PowerVSVolume `json:"volumes,omitempty"`
+
 	// Network is the reference to the Network to use for this instance.
-	Network IBMPowerVSResourceReference `json:"network"`
+	// Defaults to the network of the cluster.
+	// +optional
+	Network IBMPowerVSResourceReference `json:"network,omitempty"`
+
+	// Networks are the networks the instance is attached to, the first one is the primary network.
+	// It cannot be set with Network, and defaults to Network when not set.
+	// +optional
+	Networks []PowerVSMachineNetwork `json:"networks,omitempty"`
 
 	// ProviderID is the unique identifier as specified by the cloud provider.
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
+
+	// Tags are the user tags attached to the instance in addition to the tags identifying the cluster.
+	// Tags removed from the list are not detached from the instance.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// AccessTags are the access management tags attached to the instance.
+	// The tags must already exist in the account. Tags removed from the list are not detached from the instance.
+	// +optional
+	AccessTags []string `json:"accessTags,omitempty"`
+
+	// AdditionalUserData are references to Secrets holding cloud-init parts merged with the bootstrap data
+	// into a multi-part MIME user data. The parts are ordered as listed, before the bootstrap data.
+	// Only bootstrap data in cloud-config format can be merged.
+	// +optional
+	AdditionalUserData []UserDataSecretReference `json:"additionalUserData,omitempty"`
 }
 
 // IBMPowerVSResourceReference is a reference to a specific PowerVS resource by ID or Name
@@ -101,6 +169,133 @@ type IBMPowerVSMachineStatus struct {
 	// Fault will report if any fault messages for the vsi
 	// +optional
 	Fault string `json:"fault,omitempty"`
+
+	// BootstrapDataObject is the key of the Cloud Object Storage object holding the bootstrap data of the instance,
+	// the object is deleted once the node of the machine joined the cluster.
+	// +optional
+	BootstrapDataObject string `json:"bootstrapDataObject,omitempty"`
+
+	// NetworkIDs are the ids of the networks resolved from the spec, in order.
+	// +optional
+	NetworkIDs []string `json:"networkIDs,omitempty"`
+
+	// StorageType is the storage tier of the boot volume of the vsi.
+	// +optional
+	StorageType string `json:"storageType,omitempty"`
+
+	// StoragePool is the storage pool of the boot volume of the vsi.
+	// +optional
+	StoragePool string `json:"storagePool,omitempty"`
+
+	// Volumes are the data volumes of the instance.
+	// +optional
+	Volumes []PowerVSVolumeStatus `json:"volumes,omitempty"`
+
+	// IPClaims are the addresses claimed from IBMPowerVSIPPools, they are released when the machine is deleted.
+	// +optional
+	IPClaims []PowerVSIPClaim `json:"ipClaims,omitempty"`
+
+	// TagsHash is the hash of the tags last attached to the instance, they are attached again
+	// once the tags or the tagged resources change.
+	// +optional
+	TagsHash string `json:"tagsHash,omitempty"`
+
+	// Conditions defines current service state of the IBMPowerVSMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// PowerVSMachineNetwork describes a network of an instance.
+type PowerVSMachineNetwork struct {
+	// IBMPowerVSResourceReference is the reference to the network, the network of the cluster when neither ID
+	// nor Name is set.
+	IBMPowerVSResourceReference `json:",inline"`
+
+	// IPAddress is the static IP address of the instance on the network,
+	// it is allocated from the network when neither IPAddress nor IPPool is set.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// IPPool is the reference to the IBMPowerVSIPPool the static IP address is claimed from when the machine is
+	// created, the address is released when the machine is deleted. It cannot be set with IPAddress, the network
+	// defaults to the network of the pool.
+	// +optional
+	IPPool *v1.LocalObjectReference `json:"ipPool,omitempty"`
+}
+
+// PowerVSIPClaim is an address claimed by a machine from an IBMPowerVSIPPool for one of its networks.
+type PowerVSIPClaim struct {
+	// Pool is the name of the IBMPowerVSIPPool.
+	Pool string `json:"pool"`
+
+	// Network is the ID of the network the address is claimed for.
+	Network string `json:"network"`
+
+	// Address is the claimed address.
+	Address string `json:"address"`
+}
+
+// PowerVSStorageAffinity places the boot volume of an instance on the same storage pool (affinity) or on a different
+// storage pool (anti-affinity) than a volume or an instance. Only one of Volume or Instance may be specified.
+type PowerVSStorageAffinity struct {
+	// Policy is the storage affinity policy.
+	// +kubebuilder:validation:Enum=affinity;anti-affinity
+	Policy string `json:"policy"`
+
+	// Volume is the ID or name of the volume.
+	// +optional
+	Volume string `json:"volume,omitempty"`
+
+	// Instance is the ID or name of the instance.
+	// +optional
+	Instance string `json:"instance,omitempty"`
+}
+
+// PowerVSVolume describes a data volume of an instance.
+type PowerVSVolume struct {
+	// Name of the volume, it is created with the name <machine name>-<name>.
+	Name string `json:"name"`
+
+	// Size of the volume in GB.
+	// +kubebuilder:validation:Minimum=1
+	Size int64 `json:"size"`
+
+	// Type of the volume.
+	// +kubebuilder:validation:Enum=tier1;tier3
+	// +kubebuilder:default=tier1
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Shareable allows the volume to be attached to multiple instances.
+	// +optional
+	Shareable bool `json:"shareable,omitempty"`
+
+	// AffinityPolicy places the volume on the same storage (affinity) or on a different storage (anti-affinity)
+	// than the volume named AffinityVolume.
+	// +kubebuilder:validation:Enum=affinity;anti-affinity
+	// +optional
+	AffinityPolicy string `json:"affinityPolicy,omitempty"`
+
+	// AffinityVolume is the name of a volume of the machine listed before this one, required with AffinityPolicy.
+	// +optional
+	AffinityVolume string `json:"affinityVolume,omitempty"`
+
+	// Retain keeps the volume when the instance is deleted.
+	// +optional
+	Retain bool `json:"retain,omitempty"`
+}
+
+// PowerVSVolumeStatus describes a data volume of an instance.
+type PowerVSVolumeStatus struct {
+	// Name of the volume in the spec.
+	Name string `json:"name"`
+
+	// ID of the volume.
+	ID string `json:"id"`
+
+	// State of the volume.
+	// +optional
+	State string `json:"state,omitempty"`
 }
 
 // +kubebuilder:subresource:status
@@ -119,6 +314,16 @@ type IBMPowerVSMachine struct {
 	Status IBMPowerVSMachineStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the observations of the operational state of the IBMPowerVSMachine resource.
+func (r *IBMPowerVSMachine) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMPowerVSMachine to the predescribed clusterv1.Conditions.
+func (r *IBMPowerVSMachine) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // IBMPowerVSMachineList contains a list of IBMPowerVSMachine
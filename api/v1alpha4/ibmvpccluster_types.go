@@ -39,7 +39,12 @@ type IBMVPCClusterSpec struct {
 	Region string `json:"region"`
 
 	// The VPC resources should be created under the resource group
-	ResourceGroup string `json:"resourceGroup"`
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// ResourceGroupRef is the reference to the resource group by id or name, used when ResourceGroup is not set.
+	// +optional
+	ResourceGroupRef *IBMVPCResourceReference `json:"resourceGroupRef,omitempty"`
 
 	// The Name of VPC
 	VPC string `json:"vpc,omitempty"`
@@ -50,6 +55,32 @@ type IBMVPCClusterSpec struct {
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// ControlPlanePlacementGroup configures the placement group created to spread the control plane machines.
+	// +optional
+	ControlPlanePlacementGroup *VPCPlacementGroup `json:"controlPlanePlacementGroup,omitempty"`
+
+	// Tags are the user tags attached to every resource created for the cluster, in addition
+	// to the tags identifying the cluster. Machines inherit them. Tags removed from the list are not
+	// detached from the resources.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// AccessTags are the access management tags attached to every resource created for the cluster.
+	// The tags must already exist in the account. Machines inherit them. Tags removed from the list
+	// are not detached from the resources.
+	// +optional
+	AccessTags []string `json:"accessTags,omitempty"`
+
+	// TrustedProfile is the ID of the IAM trusted profile linked to the machines of the cluster
+	// that do not reference a trusted profile of their own.
+	// +optional
+	TrustedProfile string `json:"trustedProfile,omitempty"`
+
+	// BootstrapStorage delivers the bootstrap data of the machines through a Cloud Object Storage bucket
+	// instead of the instance user data, which is limited in size.
+	// +optional
+	BootstrapStorage *BootstrapStorage `json:"bootstrapStorage,omitempty"`
 }
 
 // IBMVPCClusterStatus defines the observed state of IBMVPCCluster
@@ -61,6 +92,27 @@ type IBMVPCClusterStatus struct {
 	Ready       bool        `json:"ready"`
 	Subnet      Subnet      `json:"subnet,omitempty"`
 	APIEndpoint APIEndpoint `json:"apiEndpoint,omitempty"`
+
+	// ResourceGroupID is the id of the resource group resolved from the spec.
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
+
+	// ControlPlanePlacementGroupID is the id of the placement group created for the control plane machines.
+	// +optional
+	ControlPlanePlacementGroupID string `json:"controlPlanePlacementGroupID,omitempty"`
+
+	// BootstrapBucket is the bucket of the bootstrap data when it is delivered through Cloud Object Storage.
+	// +optional
+	BootstrapBucket *BootstrapBucketStatus `json:"bootstrapBucket,omitempty"`
+
+	// TagsHash is the hash of the tags last attached to the resources of the cluster, they are attached again
+	// once the tags or the tagged resources change.
+	// +optional
+	TagsHash string `json:"tagsHash,omitempty"`
+
+	// Conditions defines current service state of the IBMVPCCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 // VPC holds the VPC information
@@ -85,6 +137,16 @@ type IBMVPCCluster struct {
 	Status IBMVPCClusterStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the observations of the operational state of the IBMVPCCluster resource.
+func (r *IBMVPCCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMVPCCluster to the predescribed clusterv1.Conditions.
+func (r *IBMVPCCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // IBMVPCClusterList contains a list of IBMVPCCluster
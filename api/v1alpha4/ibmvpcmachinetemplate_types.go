@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // IBMVPCMachineTemplateSpec defines the desired state of IBMVPCMachineTemplate
@@ -31,16 +33,40 @@ type IBMVPCMachineTemplateResource struct {
 	Spec IBMVPCMachineSpec `json:"spec"`
 }
 
+// IBMVPCMachineTemplateStatus defines the observed state of IBMVPCMachineTemplate
+type IBMVPCMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the instance profile of the template,
+	// it is used by the cluster autoscaler to scale node groups from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// Conditions defines current service state of the IBMVPCMachineTemplate.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ibmvpcmachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // IBMVPCMachineTemplate is the Schema for the IBMVPCMachinetemplates API
 type IBMVPCMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec IBMVPCMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   IBMVPCMachineTemplateSpec   `json:"spec,omitempty"`
+	Status IBMVPCMachineTemplateStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the observations of the operational state of the IBMVPCMachineTemplate resource.
+func (r *IBMVPCMachineTemplate) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMVPCMachineTemplate to the predescribed clusterv1.Conditions.
+func (r *IBMVPCMachineTemplate) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
 }
 
 // +kubebuilder:object:root=true
@@ -22,7 +22,9 @@ package v1alpha4
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -50,13 +52,48 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapBucketStatus) DeepCopyInto(out *BootstrapBucketStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapBucketStatus.
+func (in *BootstrapBucketStatus) DeepCopy() *BootstrapBucketStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapBucketStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStorage) DeepCopyInto(out *BootstrapStorage) {
+	*out = *in
+	if in.URLExpiry != nil {
+		in, out := &in.URLExpiry, &out.URLExpiry
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStorage.
+func (in *BootstrapStorage) DeepCopy() *BootstrapStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMPowerVSCluster) DeepCopyInto(out *IBMPowerVSCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSCluster.
@@ -112,8 +149,43 @@ func (in *IBMPowerVSClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMPowerVSClusterSpec) DeepCopyInto(out *IBMPowerVSClusterSpec) {
 	*out = *in
+	if in.ServiceInstance != nil {
+		in, out := &in.ServiceInstance, &out.ServiceInstance
+		*out = new(PowerVSServiceInstance)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.PrivateNetwork != nil {
+		in, out := &in.PrivateNetwork, &out.PrivateNetwork
+		*out = new(PowerVSPrivateNetwork)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlaneVIP != nil {
+		in, out := &in.ControlPlaneVIP, &out.ControlPlaneVIP
+		*out = new(PowerVSControlPlaneVIP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlacementGroups != nil {
+		in, out := &in.PlacementGroups, &out.PlacementGroups
+		*out = make([]PowerVSPlacementGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedProcessorPool != nil {
+		in, out := &in.SharedProcessorPool, &out.SharedProcessorPool
+		*out = new(PowerVSSharedProcessorPool)
+		**out = **in
+	}
+	if in.SSHKeySecret != nil {
+		in, out := &in.SSHKeySecret, &out.SSHKeySecret
+		*out = new(SSHKeySecretReference)
+		**out = **in
+	}
+	if in.BootstrapStorage != nil {
+		in, out := &in.BootstrapStorage, &out.BootstrapStorage
+		*out = new(BootstrapStorage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSClusterSpec.
@@ -129,6 +201,41 @@ func (in *IBMPowerVSClusterSpec) DeepCopy() *IBMPowerVSClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMPowerVSClusterStatus) DeepCopyInto(out *IBMPowerVSClusterStatus) {
 	*out = *in
+	if in.ServiceInstance != nil {
+		in, out := &in.ServiceInstance, &out.ServiceInstance
+		*out = new(PowerVSServiceInstanceStatus)
+		**out = **in
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(PowerVSNetworkStatus)
+		**out = **in
+	}
+	if in.ControlPlaneVIP != nil {
+		in, out := &in.ControlPlaneVIP, &out.ControlPlaneVIP
+		*out = new(PowerVSControlPlaneVIPStatus)
+		**out = **in
+	}
+	if in.PlacementGroups != nil {
+		in, out := &in.PlacementGroups, &out.PlacementGroups
+		*out = make([]PowerVSPlacementGroupStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedProcessorPool != nil {
+		in, out := &in.SharedProcessorPool, &out.SharedProcessorPool
+		*out = new(PowerVSSharedProcessorPoolStatus)
+		**out = **in
+	}
+	if in.SSHKey != nil {
+		in, out := &in.SSHKey, &out.SSHKey
+		*out = new(PowerVSSSHKeyStatus)
+		**out = **in
+	}
+	if in.BootstrapBucket != nil {
+		in, out := &in.BootstrapBucket, &out.BootstrapBucket
+		*out = new(BootstrapBucketStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSClusterStatus.
@@ -141,6 +248,202 @@ func (in *IBMPowerVSClusterStatus) DeepCopy() *IBMPowerVSClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSIPPool) DeepCopyInto(out *IBMPowerVSIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSIPPool.
+func (in *IBMPowerVSIPPool) DeepCopy() *IBMPowerVSIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMPowerVSIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSIPPoolList) DeepCopyInto(out *IBMPowerVSIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IBMPowerVSIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSIPPoolList.
+func (in *IBMPowerVSIPPoolList) DeepCopy() *IBMPowerVSIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMPowerVSIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSIPPoolSpec) DeepCopyInto(out *IBMPowerVSIPPoolSpec) {
+	*out = *in
+	in.Network.DeepCopyInto(&out.Network)
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]IPRange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSIPPoolSpec.
+func (in *IBMPowerVSIPPoolSpec) DeepCopy() *IBMPowerVSIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSIPPoolStatus) DeepCopyInto(out *IBMPowerVSIPPoolStatus) {
+	*out = *in
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make([]IPAllocation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSIPPoolStatus.
+func (in *IBMPowerVSIPPoolStatus) DeepCopy() *IBMPowerVSIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSImage) DeepCopyInto(out *IBMPowerVSImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSImage.
+func (in *IBMPowerVSImage) DeepCopy() *IBMPowerVSImage {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMPowerVSImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSImageList) DeepCopyInto(out *IBMPowerVSImageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IBMPowerVSImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSImageList.
+func (in *IBMPowerVSImageList) DeepCopy() *IBMPowerVSImageList {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSImageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMPowerVSImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSImageSpec) DeepCopyInto(out *IBMPowerVSImageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSImageSpec.
+func (in *IBMPowerVSImageSpec) DeepCopy() *IBMPowerVSImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMPowerVSImageStatus) DeepCopyInto(out *IBMPowerVSImageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSImageStatus.
+func (in *IBMPowerVSImageStatus) DeepCopy() *IBMPowerVSImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMPowerVSImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMPowerVSMachine) DeepCopyInto(out *IBMPowerVSMachine) {
 	*out = *in
@@ -204,12 +507,59 @@ func (in *IBMPowerVSMachineList) DeepCopyObject() runtime.Object {
 func (in *IBMPowerVSMachineSpec) DeepCopyInto(out *IBMPowerVSMachineSpec) {
 	*out = *in
 	in.Image.DeepCopyInto(&out.Image)
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SharedProcessorPool != nil {
+		in, out := &in.SharedProcessorPool, &out.SharedProcessorPool
+		*out = new(IBMPowerVSResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAffinity != nil {
+		in, out := &in.StorageAffinity, &out.StorageAffinity
+		*out = new(PowerVSStorageAffinity)
+		**out = **in
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(IBMPowerVSResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]PowerVSVolume, len(*in))
+		copy(*out, *in)
+	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]PowerVSMachineNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ProviderID != nil {
 		in, out := &in.ProviderID, &out.ProviderID
 		*out = new(string)
 		**out = **in
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalUserData != nil {
+		in, out := &in.AdditionalUserData, &out.AdditionalUserData
+		*out = make([]UserDataSecretReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSMachineSpec.
@@ -230,6 +580,28 @@ func (in *IBMPowerVSMachineStatus) DeepCopyInto(out *IBMPowerVSMachineStatus) {
 		*out = make([]v1.NodeAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkIDs != nil {
+		in, out := &in.NetworkIDs, &out.NetworkIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]PowerVSVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPClaims != nil {
+		in, out := &in.IPClaims, &out.IPClaims
+		*out = make([]PowerVSIPClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMPowerVSMachineStatus.
@@ -378,7 +750,7 @@ func (in *IBMVPCCluster) DeepCopyInto(out *IBMVPCCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -435,7 +807,32 @@ func (in *IBMVPCClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMVPCClusterSpec) DeepCopyInto(out *IBMVPCClusterSpec) {
 	*out = *in
+	if in.ResourceGroupRef != nil {
+		in, out := &in.ResourceGroupRef, &out.ResourceGroupRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlanePlacementGroup != nil {
+		in, out := &in.ControlPlanePlacementGroup, &out.ControlPlanePlacementGroup
+		*out = new(VPCPlacementGroup)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootstrapStorage != nil {
+		in, out := &in.BootstrapStorage, &out.BootstrapStorage
+		*out = new(BootstrapStorage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCClusterSpec.
@@ -454,6 +851,18 @@ func (in *IBMVPCClusterStatus) DeepCopyInto(out *IBMVPCClusterStatus) {
 	out.VPC = in.VPC
 	in.Subnet.DeepCopyInto(&out.Subnet)
 	in.APIEndpoint.DeepCopyInto(&out.APIEndpoint)
+	if in.BootstrapBucket != nil {
+		in, out := &in.BootstrapBucket, &out.BootstrapBucket
+		*out = new(BootstrapBucketStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCClusterStatus.
@@ -528,12 +937,17 @@ func (in *IBMVPCMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IBMVPCMachineSpec) DeepCopyInto(out *IBMVPCMachineSpec) {
 	*out = *in
+	if in.ImageRef != nil {
+		in, out := &in.ImageRef, &out.ImageRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProviderID != nil {
 		in, out := &in.ProviderID, &out.ProviderID
 		*out = new(string)
 		**out = **in
 	}
-	out.PrimaryNetworkInterface = in.PrimaryNetworkInterface
+	in.PrimaryNetworkInterface.DeepCopyInto(&out.PrimaryNetworkInterface)
 	if in.SSHKeys != nil {
 		in, out := &in.SSHKeys, &out.SSHKeys
 		*out = make([]*string, len(*in))
@@ -545,14 +959,62 @@ func (in *IBMVPCMachineSpec) DeepCopyInto(out *IBMVPCMachineSpec) {
 			}
 		}
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineSpec.
-func (in *IBMVPCMachineSpec) DeepCopy() *IBMVPCMachineSpec {
-	if in == nil {
-		return nil
+	if in.SSHKeyRefs != nil {
+		in, out := &in.SSHKeyRefs, &out.SSHKeyRefs
+		*out = make([]*IBMVPCResourceReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(IBMVPCResourceReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
 	}
-	out := new(IBMVPCMachineSpec)
+	if in.DataVolumes != nil {
+		in, out := &in.DataVolumes, &out.DataVolumes
+		*out = make([]VPCVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DedicatedHost != nil {
+		in, out := &in.DedicatedHost, &out.DedicatedHost
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DedicatedHostGroup != nil {
+		in, out := &in.DedicatedHostGroup, &out.DedicatedHostGroup
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalUserData != nil {
+		in, out := &in.AdditionalUserData, &out.AdditionalUserData
+		*out = make([]UserDataSecretReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineSpec.
+func (in *IBMVPCMachineSpec) DeepCopy() *IBMVPCMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachineSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -565,6 +1027,23 @@ func (in *IBMVPCMachineStatus) DeepCopyInto(out *IBMVPCMachineStatus) {
 		*out = make([]v1.NodeAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.SSHKeyIDs != nil {
+		in, out := &in.SSHKeyIDs, &out.SSHKeyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataVolumes != nil {
+		in, out := &in.DataVolumes, &out.DataVolumes
+		*out = make([]VPCVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineStatus.
@@ -583,6 +1062,7 @@ func (in *IBMVPCMachineTemplate) DeepCopyInto(out *IBMVPCMachineTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineTemplate.
@@ -667,9 +1147,98 @@ func (in *IBMVPCMachineTemplateSpec) DeepCopy() *IBMVPCMachineTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachineTemplateStatus) DeepCopyInto(out *IBMVPCMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachineTemplateStatus.
+func (in *IBMVPCMachineTemplateStatus) DeepCopy() *IBMVPCMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCResourceReference) DeepCopyInto(out *IBMVPCResourceReference) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCResourceReference.
+func (in *IBMVPCResourceReference) DeepCopy() *IBMVPCResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAllocation) DeepCopyInto(out *IPAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAllocation.
+func (in *IPAllocation) DeepCopy() *IPAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPRange) DeepCopyInto(out *IPRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPRange.
+func (in *IPRange) DeepCopy() *IPRange {
+	if in == nil {
+		return nil
+	}
+	out := new(IPRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
 	*out = *in
+	if in.SubnetRef != nil {
+		in, out := &in.SubnetRef, &out.SubnetRef
+		*out = new(IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
@@ -682,6 +1251,278 @@ func (in *NetworkInterface) DeepCopy() *NetworkInterface {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSControlPlaneVIP) DeepCopyInto(out *PowerVSControlPlaneVIP) {
+	*out = *in
+	if in.PublicNetwork != nil {
+		in, out := &in.PublicNetwork, &out.PublicNetwork
+		*out = new(IBMPowerVSResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSControlPlaneVIP.
+func (in *PowerVSControlPlaneVIP) DeepCopy() *PowerVSControlPlaneVIP {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSControlPlaneVIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSControlPlaneVIPStatus) DeepCopyInto(out *PowerVSControlPlaneVIPStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSControlPlaneVIPStatus.
+func (in *PowerVSControlPlaneVIPStatus) DeepCopy() *PowerVSControlPlaneVIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSControlPlaneVIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSIPClaim) DeepCopyInto(out *PowerVSIPClaim) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSIPClaim.
+func (in *PowerVSIPClaim) DeepCopy() *PowerVSIPClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSIPClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSMachineNetwork) DeepCopyInto(out *PowerVSMachineNetwork) {
+	*out = *in
+	in.IBMPowerVSResourceReference.DeepCopyInto(&out.IBMPowerVSResourceReference)
+	if in.IPPool != nil {
+		in, out := &in.IPPool, &out.IPPool
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSMachineNetwork.
+func (in *PowerVSMachineNetwork) DeepCopy() *PowerVSMachineNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSMachineNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSNetworkStatus) DeepCopyInto(out *PowerVSNetworkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSNetworkStatus.
+func (in *PowerVSNetworkStatus) DeepCopy() *PowerVSNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSPlacementGroup) DeepCopyInto(out *PowerVSPlacementGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSPlacementGroup.
+func (in *PowerVSPlacementGroup) DeepCopy() *PowerVSPlacementGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSPlacementGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSPlacementGroupStatus) DeepCopyInto(out *PowerVSPlacementGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSPlacementGroupStatus.
+func (in *PowerVSPlacementGroupStatus) DeepCopy() *PowerVSPlacementGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSPlacementGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSPrivateNetwork) DeepCopyInto(out *PowerVSPrivateNetwork) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSPrivateNetwork.
+func (in *PowerVSPrivateNetwork) DeepCopy() *PowerVSPrivateNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSPrivateNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSSSHKeyStatus) DeepCopyInto(out *PowerVSSSHKeyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSSSHKeyStatus.
+func (in *PowerVSSSHKeyStatus) DeepCopy() *PowerVSSSHKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSSSHKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSServiceInstance) DeepCopyInto(out *PowerVSServiceInstance) {
+	*out = *in
+	in.ResourceGroup.DeepCopyInto(&out.ResourceGroup)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSServiceInstance.
+func (in *PowerVSServiceInstance) DeepCopy() *PowerVSServiceInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSServiceInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSServiceInstanceStatus) DeepCopyInto(out *PowerVSServiceInstanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSServiceInstanceStatus.
+func (in *PowerVSServiceInstanceStatus) DeepCopy() *PowerVSServiceInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSServiceInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSSharedProcessorPool) DeepCopyInto(out *PowerVSSharedProcessorPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSSharedProcessorPool.
+func (in *PowerVSSharedProcessorPool) DeepCopy() *PowerVSSharedProcessorPool {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSSharedProcessorPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSSharedProcessorPoolStatus) DeepCopyInto(out *PowerVSSharedProcessorPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSSharedProcessorPoolStatus.
+func (in *PowerVSSharedProcessorPoolStatus) DeepCopy() *PowerVSSharedProcessorPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSSharedProcessorPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSStorageAffinity) DeepCopyInto(out *PowerVSStorageAffinity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSStorageAffinity.
+func (in *PowerVSStorageAffinity) DeepCopy() *PowerVSStorageAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSStorageAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSVolume) DeepCopyInto(out *PowerVSVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSVolume.
+func (in *PowerVSVolume) DeepCopy() *PowerVSVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerVSVolumeStatus) DeepCopyInto(out *PowerVSVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerVSVolumeStatus.
+func (in *PowerVSVolumeStatus) DeepCopy() *PowerVSVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerVSVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeySecretReference) DeepCopyInto(out *SSHKeySecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeySecretReference.
+func (in *SSHKeySecretReference) DeepCopy() *SSHKeySecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeySecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subnet) DeepCopyInto(out *Subnet) {
 	*out = *in
@@ -717,6 +1558,21 @@ func (in *Subnet) DeepCopy() *Subnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSecretReference) DeepCopyInto(out *UserDataSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSecretReference.
+func (in *UserDataSecretReference) DeepCopy() *UserDataSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPC) DeepCopyInto(out *VPC) {
 	*out = *in
@@ -731,3 +1587,53 @@ func (in *VPC) DeepCopy() *VPC {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCPlacementGroup) DeepCopyInto(out *VPCPlacementGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCPlacementGroup.
+func (in *VPCPlacementGroup) DeepCopy() *VPCPlacementGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCPlacementGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCVolume) DeepCopyInto(out *VPCVolume) {
+	*out = *in
+	if in.DeleteOnInstanceDelete != nil {
+		in, out := &in.DeleteOnInstanceDelete, &out.DeleteOnInstanceDelete
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCVolume.
+func (in *VPCVolume) DeepCopy() *VPCVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCVolumeStatus) DeepCopyInto(out *VPCVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCVolumeStatus.
+func (in *VPCVolumeStatus) DeepCopy() *VPCVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
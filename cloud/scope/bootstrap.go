@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+const (
+	// bootstrapFormatCloudConfig is the format of cloud-init bootstrap data, the default when the bootstrap secret has no format.
+	bootstrapFormatCloudConfig = "cloud-config"
+	// bootstrapFormatIgnition is the format of Ignition bootstrap data.
+	bootstrapFormatIgnition = "ignition"
+)
+
+// bootstrapUserData returns the user data of an instance from its bootstrap data in the given format,
+// the bootstrap data is included from dataURL when it is not empty.
+func bootstrapUserData(format string, data []byte, dataURL string, options ignitionOptions) ([]byte, error) {
+	switch format {
+	case "", bootstrapFormatCloudConfig:
+		if dataURL != "" {
+			return []byte(fmt.Sprintf("#include\n%s\n", dataURL)), nil
+		}
+		return data, nil
+	case bootstrapFormatIgnition:
+		return ignitionUserData(data, dataURL, options)
+	default:
+		return nil, fmt.Errorf("unsupported bootstrap data format %q", format)
+	}
+}
+
+// defaultBootstrapDataURLExpiry is how long the presigned URL of the bootstrap data is valid by default. The URL is
+// only used by the first boot of the instance, clusters with slower provisioning raise the URLExpiry of the storage.
+const defaultBootstrapDataURLExpiry = 30 * time.Minute
+
+// bootstrapDataStore stores the bootstrap data of machines in a Cloud Object Storage bucket.
+type bootstrapDataStore struct {
+	client    *pkg.COSClient
+	bucket    string
+	urlExpiry time.Duration
+}
+
+func newBootstrapDataStore(storage *v1alpha4.BootstrapStorage, clusterName string) (*bootstrapDataStore, error) {
+	accessKeyID, secretAccessKey, err := cosCredentials()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get credentials to store bootstrap data in Cloud Object Storage")
+	}
+	client, err := pkg.NewCOSClient(storage.Endpoint, storage.Region, accessKeyID, secretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &bootstrapDataStore{
+		client:    client,
+		bucket:    storage.Bucket,
+		urlExpiry: defaultBootstrapDataURLExpiry,
+	}
+	if store.bucket == "" {
+		store.bucket = fmt.Sprintf("%s-bootstrap", clusterName)
+	}
+	if storage.URLExpiry != nil {
+		store.urlExpiry = storage.URLExpiry.Duration
+	}
+	// Presigned URLs are valid for at most seven days.
+	if store.urlExpiry <= 0 || store.urlExpiry > 7*24*time.Hour {
+		return nil, fmt.Errorf("bootstrap data URL expiry %s is not between 0 and 168h", store.urlExpiry)
+	}
+	return store, nil
+}
+
+// cosCredentials returns the HMAC credentials of Cloud Object Storage, they are read from the credentials file
+// or the environment like the IBM Cloud API key.
+func cosCredentials() (string, string, error) {
+	properties, err := core.GetServiceProperties("cos")
+	if err != nil {
+		return "", "", err
+	}
+	accessKeyID, secretAccessKey := properties["ACCESS_KEY_ID"], properties["SECRET_ACCESS_KEY"]
+	if accessKeyID == "" || secretAccessKey == "" {
+		return "", "", errors.New("COS_ACCESS_KEY_ID and COS_SECRET_ACCESS_KEY must be set")
+	}
+	return accessKeyID, secretAccessKey, nil
+}
+
+// bootstrapDataObjectKey returns the key of the object holding the bootstrap data of a machine.
+func bootstrapDataObjectKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// EnsureBucket creates the bucket of the store if it does not exist, it returns whether the bucket was created.
+func (s *bootstrapDataStore) EnsureBucket() (bool, error) {
+	return s.client.EnsureBucket(s.bucket)
+}
+
+// DeleteBucket deletes the bucket of the store, it fails while the bucket still holds bootstrap data.
+func (s *bootstrapDataStore) DeleteBucket() error {
+	return s.client.DeleteBucket(s.bucket)
+}
+
+// Put uploads the bootstrap data to the bucket reconciled with the cluster and returns a presigned URL downloading it.
+func (s *bootstrapDataStore) Put(key string, data []byte) (string, error) {
+	if err := s.client.PutObject(s.bucket, key, data); err != nil {
+		return "", errors.Wrap(err, "failed to upload bootstrap data")
+	}
+	return s.client.PresignGetObject(s.bucket, key, s.urlExpiry), nil
+}
+
+// Delete deletes the bootstrap data.
+func (s *bootstrapDataStore) Delete(key string) error {
+	return s.client.DeleteObject(s.bucket, key)
+}
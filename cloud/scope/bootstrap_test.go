@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func TestNewBootstrapDataStore(t *testing.T) {
+	for key, value := range map[string]string{"COS_ACCESS_KEY_ID": "access-key", "COS_SECRET_ACCESS_KEY": "secret-key"} {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	tests := []struct {
+		name       string
+		urlExpiry  *metav1.Duration
+		wantExpiry time.Duration
+		wantErr    bool
+	}{
+		{
+			name:       "defaults the URL expiry to half an hour",
+			wantExpiry: 30 * time.Minute,
+		},
+		{
+			name:       "uses the URL expiry of the spec",
+			urlExpiry:  &metav1.Duration{Duration: 2 * time.Hour},
+			wantExpiry: 2 * time.Hour,
+		},
+		{
+			name:      "rejects URL expiries longer than presigned URLs allow",
+			urlExpiry: &metav1.Duration{Duration: 8 * 24 * time.Hour},
+			wantErr:   true,
+		},
+		{
+			name:      "rejects negative URL expiries",
+			urlExpiry: &metav1.Duration{Duration: -time.Minute},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			store, err := newBootstrapDataStore(&v1alpha4.BootstrapStorage{
+				Endpoint:  "https://s3.us-south.cloud-object-storage.appdomain.cloud",
+				Region:    "us-south",
+				URLExpiry: tt.urlExpiry,
+			}, "capi")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(store.bucket).To(Equal("capi-bootstrap"))
+			g.Expect(store.urlExpiry).To(Equal(tt.wantExpiry))
+		})
+	}
+}
+
+// fakeBuckets is a stand-in of the bucket operations of the S3 compatible API.
+type fakeBuckets map[string]bool
+
+func (b fakeBuckets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodPut:
+		b[bucket] = true
+	case !b[bucket]:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodDelete:
+		delete(b, bucket)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestReconcileBootstrapBucket(t *testing.T) {
+	for key, value := range map[string]string{"COS_ACCESS_KEY_ID": "access-key", "COS_SECRET_ACCESS_KEY": "secret-key"} {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	tests := []struct {
+		name           string
+		bucket         string
+		existing       bool
+		wantCreated    bool
+		wantDeleted    bool
+		wantBucketName string
+	}{
+		{
+			name:           "creates and deletes the default bucket",
+			wantCreated:    true,
+			wantDeleted:    true,
+			wantBucketName: "capi-bootstrap",
+		},
+		{
+			name:           "uses an existing bucket without deleting it",
+			bucket:         "shared",
+			existing:       true,
+			wantBucketName: "shared",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			buckets := fakeBuckets{}
+			if tt.existing {
+				buckets[tt.bucket] = true
+			}
+			server := httptest.NewServer(buckets)
+			defer server.Close()
+
+			scope := newPowerVSClusterScope(t, newFakePowerVS(), v1alpha4.IBMPowerVSClusterSpec{
+				BootstrapStorage: &v1alpha4.BootstrapStorage{Endpoint: server.URL, Region: "us-south", Bucket: tt.bucket},
+			})
+			g.Expect(scope.ReconcileBootstrapBucket()).To(Succeed())
+			g.Expect(scope.IBMPowerVSCluster.Status.BootstrapBucket).To(Equal(&v1alpha4.BootstrapBucketStatus{
+				Name:              tt.wantBucketName,
+				ControllerCreated: tt.wantCreated,
+			}))
+			g.Expect(buckets).To(HaveKey(tt.wantBucketName))
+
+			// The bucket is only ensured once.
+			delete(buckets, tt.wantBucketName)
+			g.Expect(scope.ReconcileBootstrapBucket()).To(Succeed())
+			g.Expect(buckets).NotTo(HaveKey(tt.wantBucketName))
+			buckets[tt.wantBucketName] = true
+
+			g.Expect(scope.DeleteBootstrapBucket()).To(Succeed())
+			g.Expect(scope.IBMPowerVSCluster.Status.BootstrapBucket).To(BeNil())
+			if tt.wantDeleted {
+				g.Expect(buckets).NotTo(HaveKey(tt.wantBucketName))
+			} else {
+				g.Expect(buckets).To(HaveKey(tt.wantBucketName))
+			}
+		})
+	}
+}
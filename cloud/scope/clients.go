@@ -17,13 +17,24 @@ limitations under the License.
 package scope
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
 	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
 )
 
 // IBMVPCClients hosts the IBM VPC service
 type IBMVPCClients struct {
-	VPCService *vpcv1.VpcV1
+	VPCService    *vpcv1.VpcV1
+	TaggingClient *pkg.GlobalTaggingClient
+	IAMClient     *pkg.IAMIdentityClient
 	//APIKey          string
 	//IAMEndpoint     string
 	//ServiceEndPoint string
@@ -38,3 +49,257 @@ func (c *IBMVPCClients) setIBMVPCService(authenticator core.Authenticator, svcEn
 
 	return err
 }
+
+func (c *IBMVPCClients) setGlobalTaggingService(authenticator core.Authenticator) error {
+	var err error
+	// TODO: Will be removed once we find a better way of overriding the service endpoint
+	c.TaggingClient, err = pkg.NewGlobalTaggingClient(authenticator, os.Getenv("TAGGING_SERVICE_ENDPOINT"))
+
+	return err
+}
+
+func (c *IBMVPCClients) setIAMIdentityService(authenticator core.Authenticator) error {
+	var err error
+	c.IAMClient, err = pkg.NewIAMIdentityClient(authenticator, os.Getenv("IAM_IDENTITY_SERVICE_ENDPOINT"))
+
+	return err
+}
+
+func (c *IBMVPCClients) getImageID(image infrav1.IBMVPCResourceReference) (*string, error) {
+	if image.ID != nil {
+		return image.ID, nil
+	} else if image.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+
+	var ids []string
+	options := &vpcv1.ListImagesOptions{}
+	options.SetName(*image.Name)
+	for {
+		images, _, err := c.VPCService.ListImages(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range images.Images {
+			if *img.Name == *image.Name {
+				ids = append(ids, *img.ID)
+			}
+		}
+		start, err := images.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+	return uniqueResourceID("image", *image.Name, ids)
+}
+
+func (c *IBMVPCClients) getSSHKeyIDs(sshKeys []*infrav1.IBMVPCResourceReference) ([]string, error) {
+	if sshKeys == nil {
+		return nil, nil
+	}
+
+	var keys []vpcv1.Key
+	sshKeyIDs := []string{}
+	for _, sshKey := range sshKeys {
+		if sshKey == nil {
+			continue
+		}
+		if sshKey.ID != nil {
+			sshKeyIDs = append(sshKeyIDs, *sshKey.ID)
+			continue
+		} else if sshKey.Name == nil {
+			return nil, fmt.Errorf("both ID and Name of the SSH key can't be nil")
+		}
+
+		if keys == nil {
+			var err error
+			if keys, err = c.listKeys(); err != nil {
+				return nil, err
+			}
+		}
+		var ids []string
+		for _, key := range keys {
+			if *key.Name == *sshKey.Name {
+				ids = append(ids, *key.ID)
+			}
+		}
+		id, err := uniqueResourceID("SSH key", *sshKey.Name, ids)
+		if err != nil {
+			return nil, err
+		}
+		sshKeyIDs = append(sshKeyIDs, *id)
+	}
+	return sshKeyIDs, nil
+}
+
+func (c *IBMVPCClients) listKeys() ([]vpcv1.Key, error) {
+	var keys []vpcv1.Key
+	options := &vpcv1.ListKeysOptions{}
+	for {
+		collection, _, err := c.VPCService.ListKeys(options)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, collection.Keys...)
+		start, err := collection.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			return keys, nil
+		}
+		options.SetStart(*start)
+	}
+}
+
+// getSubnetID resolves the subnet reference, subnets looked up by name are restricted to the VPC when it is known.
+func (c *IBMVPCClients) getSubnetID(subnet *infrav1.IBMVPCResourceReference, vpcID string) (*string, error) {
+	if subnet == nil {
+		return nil, fmt.Errorf("subnet of the primary network interface is not set")
+	} else if subnet.ID != nil {
+		return subnet.ID, nil
+	} else if subnet.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+
+	var ids []string
+	options := &vpcv1.ListSubnetsOptions{}
+	for {
+		subnets, _, err := c.VPCService.ListSubnets(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, sn := range subnets.Subnets {
+			if *sn.Name != *subnet.Name {
+				continue
+			}
+			if vpcID != "" && sn.VPC != nil && *sn.VPC.ID != vpcID {
+				continue
+			}
+			ids = append(ids, *sn.ID)
+		}
+		start, err := subnets.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+	return uniqueResourceID("subnet", *subnet.Name, ids)
+}
+
+// uniqueResourceID returns the only id found for a resource name, a name matching several resources is ambiguous.
+func uniqueResourceID(kind, name string, ids []string) (*string, error) {
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("failed to find a %s with name %s", kind, name)
+	case 1:
+		return &ids[0], nil
+	default:
+		return nil, fmt.Errorf("found %d resources of type %s with name %s, use the ID to reference it", len(ids), kind, name)
+	}
+}
+
+// getInstanceProfile returns the instance profile with the given name, or nil if the profile does not exist in the region.
+func (c *IBMVPCClients) getInstanceProfile(name string) (*vpcv1.InstanceProfile, error) {
+	options := &vpcv1.GetInstanceProfileOptions{}
+	options.SetName(name)
+	profile, response, err := c.VPCService.GetInstanceProfile(options)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// instanceMetadataAPIVersion is the VPC API version creating instances with the metadata service and a default trusted profile.
+const instanceMetadataAPIVersion = "2022-03-29"
+
+// instancePrototypeWithMetadata adds the metadata_service and default_trusted_profile fields, which the VPC SDK
+// does not model, to the fields of the embedded instance prototype.
+type instancePrototypeWithMetadata struct {
+	*vpcv1.InstancePrototype
+	MetadataService       *instanceMetadataServicePrototype `json:"metadata_service,omitempty"`
+	DefaultTrustedProfile *instanceTrustedProfilePrototype  `json:"default_trusted_profile,omitempty"`
+}
+
+type instanceMetadataServicePrototype struct {
+	Enabled bool `json:"enabled"`
+}
+
+type instanceTrustedProfilePrototype struct {
+	Target struct {
+		ID string `json:"id"`
+	} `json:"target"`
+	AutoLink bool `json:"auto_link"`
+}
+
+// createInstance creates an instance from the prototype with the metadata service enabled and the trusted profile linked
+// when trustedProfileID is not empty. The request uses the API version supporting these fields, the instance is either
+// created with them or not created at all.
+func (c *IBMVPCClients) createInstance(prototype *vpcv1.InstancePrototype, trustedProfileID string) (*vpcv1.Instance, error) {
+	vpcService, err := vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
+		URL:           c.VPCService.Service.GetServiceURL(),
+		Authenticator: c.VPCService.Service.Options.Authenticator,
+		Version:       core.StringPtr(instanceMetadataAPIVersion),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instancePrototype := &instancePrototypeWithMetadata{
+		InstancePrototype: prototype,
+		MetadataService:   &instanceMetadataServicePrototype{Enabled: true},
+	}
+	if trustedProfileID != "" {
+		instancePrototype.DefaultTrustedProfile = &instanceTrustedProfilePrototype{AutoLink: true}
+		instancePrototype.DefaultTrustedProfile.Target.ID = trustedProfileID
+	}
+	options := &vpcv1.CreateInstanceOptions{}
+	options.SetInstancePrototype(instancePrototype)
+	instance, response, err := vpcService.CreateInstance(options)
+	if err != nil {
+		return nil, instanceCreateError(err, response)
+	}
+	return instance, nil
+}
+
+// instanceCreateError returns the error of a failed instance creation with the code, message and target of each error
+// of the response body, so that a rejected metadata_service or default_trusted_profile field is reported as such.
+func instanceCreateError(err error, response *core.DetailedResponse) error {
+	if response == nil {
+		return err
+	}
+	body, ok := response.GetResult().(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("failed to create instance, status code %d: %v", response.GetStatusCode(), err)
+	}
+	data, marshalErr := json.Marshal(body["errors"])
+	if marshalErr != nil {
+		return err
+	}
+	var vpcErrors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Target  struct {
+			Name string `json:"name"`
+		} `json:"target"`
+	}
+	if json.Unmarshal(data, &vpcErrors) != nil || len(vpcErrors) == 0 {
+		return fmt.Errorf("failed to create instance, status code %d: %v", response.GetStatusCode(), err)
+	}
+	details := make([]string, 0, len(vpcErrors))
+	for _, e := range vpcErrors {
+		detail := fmt.Sprintf("%s: %s", e.Code, e.Message)
+		if e.Target.Name != "" {
+			detail = fmt.Sprintf("%s (%s)", detail, e.Target.Name)
+		}
+		details = append(details, detail)
+	}
+	return fmt.Errorf("failed to create instance, status code %d: %s", response.GetStatusCode(), strings.Join(details, "; "))
+}
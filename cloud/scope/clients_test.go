@@ -0,0 +1,353 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+)
+
+func newFakeVPCClients(t *testing.T, handler http.HandlerFunc) IBMVPCClients {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	vpcService, err := vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
+		URL:           server.URL,
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return IBMVPCClients{VPCService: vpcService}
+}
+
+func TestCreateInstance(t *testing.T) {
+	tests := []struct {
+		name             string
+		trustedProfileID string
+		status           int
+		response         string
+		wantErr          string
+		wantFields       []string
+	}{
+		{
+			name:       "enables the metadata service",
+			status:     http.StatusCreated,
+			response:   `{"id":"instance","metadata_service":{"enabled":true}}`,
+			wantFields: []string{"metadata_service", "name", "profile", "zone"},
+		},
+		{
+			name:             "links the trusted profile",
+			trustedProfileID: "profile",
+			status:           http.StatusCreated,
+			response:         `{"id":"instance","metadata_service":{"enabled":true}}`,
+			wantFields:       []string{"default_trusted_profile", "metadata_service", "name", "profile", "zone"},
+		},
+		{
+			name:             "reports every error of the response body",
+			trustedProfileID: "profile",
+			status:           http.StatusBadRequest,
+			response: `{"errors":[{"code":"validation_unknown_field","message":"unknown field","target":{"name":"default_trusted_profile"}},` +
+				`{"code":"validation_required_field","message":"field is required"}]}`,
+			wantErr: "failed to create instance, status code 400: validation_unknown_field: unknown field (default_trusted_profile); " +
+				"validation_required_field: field is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var body map[string]interface{}
+			clients := newFakeVPCClients(t, func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(Equal("/instances"))
+				g.Expect(r.Method).To(Equal(http.MethodPost))
+				g.Expect(r.URL.Query().Get("version")).To(Equal(instanceMetadataAPIVersion))
+				g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			})
+
+			prototype := &vpcv1.InstancePrototype{
+				Name:    core.StringPtr("machine"),
+				Profile: &vpcv1.InstanceProfileIdentity{Name: core.StringPtr("bx2-4x16")},
+				Zone:    &vpcv1.ZoneIdentity{Name: core.StringPtr("us-south-1")},
+			}
+			instance, err := clients.createInstance(prototype, tt.trustedProfileID)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*instance.ID).To(Equal("instance"))
+
+			fields := []string{}
+			for field := range body {
+				fields = append(fields, field)
+			}
+			g.Expect(fields).To(ConsistOf(tt.wantFields))
+			if tt.trustedProfileID != "" {
+				g.Expect(body["default_trusted_profile"]).To(Equal(map[string]interface{}{
+					"target":    map[string]interface{}{"id": "profile"},
+					"auto_link": true,
+				}))
+			}
+		})
+	}
+}
+
+// fakeVPCCollections serves the VPC resources listed under each collection path, two per page,
+// filtered by the name query parameter when it is set. The items are listed under the last path segment.
+type fakeVPCCollections map[string][]map[string]interface{}
+
+func (f fakeVPCCollections) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	items, ok := f[r.URL.Path]
+	if !ok || r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if name := r.URL.Query().Get("name"); name != "" {
+		var named []map[string]interface{}
+		for _, item := range items {
+			if item["name"] == name {
+				named = append(named, item)
+			}
+		}
+		items = named
+	}
+	start := 0
+	if s := r.URL.Query().Get("start"); s != "" {
+		fmt.Sscanf(s, "%d", &start)
+	}
+	end := start + 2
+	if end > len(items) {
+		end = len(items)
+	}
+	page := map[string]interface{}{
+		path.Base(r.URL.Path): items[start:end],
+		"limit":               2,
+	}
+	if end < len(items) {
+		page["next"] = map[string]string{"href": fmt.Sprintf("https://vpc.example%s?start=%d", r.URL.Path, end)}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func vpcResource(id, name string) map[string]interface{} {
+	return map[string]interface{}{"id": id, "name": name}
+}
+
+func TestUniqueResourceID(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		wantID  string
+		wantErr string
+	}{
+		{
+			name:    "fails without resource",
+			wantErr: "failed to find a image with name ubuntu",
+		},
+		{
+			name:   "returns the only resource",
+			ids:    []string{"image"},
+			wantID: "image",
+		},
+		{
+			name:    "fails on an ambiguous name",
+			ids:     []string{"image-1", "image-2"},
+			wantErr: "found 2 resources of type image with name ubuntu, use the ID to reference it",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			id, err := uniqueResourceID("image", "ubuntu", tt.ids)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*id).To(Equal(tt.wantID))
+		})
+	}
+}
+
+func TestGetImageID(t *testing.T) {
+	images := fakeVPCCollections{"/images": {
+		vpcResource("image-1", "centos"), vpcResource("image-2", "rhel"), vpcResource("image-3", "ubuntu"),
+		vpcResource("image-4", "debian"), vpcResource("image-5", "ubuntu"), vpcResource("image-6", "fedora"),
+	}}
+
+	tests := []struct {
+		name    string
+		image   infrav1.IBMVPCResourceReference
+		wantID  string
+		wantErr string
+	}{
+		{
+			name:   "uses the ID",
+			image:  infrav1.IBMVPCResourceReference{ID: core.StringPtr("image")},
+			wantID: "image",
+		},
+		{
+			name:   "resolves the name on a later page",
+			image:  infrav1.IBMVPCResourceReference{Name: core.StringPtr("fedora")},
+			wantID: "image-6",
+		},
+		{
+			name:    "fails on a name of several images",
+			image:   infrav1.IBMVPCResourceReference{Name: core.StringPtr("ubuntu")},
+			wantErr: "found 2 resources of type image with name ubuntu, use the ID to reference it",
+		},
+		{
+			name:    "fails on an unknown name",
+			image:   infrav1.IBMVPCResourceReference{Name: core.StringPtr("windows")},
+			wantErr: "failed to find a image with name windows",
+		},
+		{
+			name:    "fails without ID and name",
+			wantErr: "both ID and Name can't be nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clients := newFakeVPCClients(t, images.ServeHTTP)
+			id, err := clients.getImageID(tt.image)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*id).To(Equal(tt.wantID))
+		})
+	}
+}
+
+func TestGetSSHKeyIDs(t *testing.T) {
+	keys := fakeVPCCollections{"/keys": {
+		vpcResource("key-1", "alice"), vpcResource("key-2", "bob"), vpcResource("key-3", "carol"), vpcResource("key-4", "bob"),
+	}}
+
+	tests := []struct {
+		name    string
+		keys    []*infrav1.IBMVPCResourceReference
+		wantIDs []string
+		wantErr string
+	}{
+		{
+			name: "returns nothing without keys",
+		},
+		{
+			name: "resolves the IDs and names in order",
+			keys: []*infrav1.IBMVPCResourceReference{
+				{Name: core.StringPtr("carol")}, nil, {ID: core.StringPtr("key")}, {Name: core.StringPtr("alice")},
+			},
+			wantIDs: []string{"key-3", "key", "key-1"},
+		},
+		{
+			name:    "fails on a name of several keys",
+			keys:    []*infrav1.IBMVPCResourceReference{{Name: core.StringPtr("bob")}},
+			wantErr: "found 2 resources of type SSH key with name bob, use the ID to reference it",
+		},
+		{
+			name:    "fails without ID and name",
+			keys:    []*infrav1.IBMVPCResourceReference{{}},
+			wantErr: "both ID and Name of the SSH key can't be nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clients := newFakeVPCClients(t, keys.ServeHTTP)
+			ids, err := clients.getSSHKeyIDs(tt.keys)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ids).To(Equal(tt.wantIDs))
+		})
+	}
+}
+
+func TestGetSubnetID(t *testing.T) {
+	subnet := func(id, name, vpcID string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name, "vpc": map[string]string{"id": vpcID}}
+	}
+	subnets := fakeVPCCollections{"/subnets": {
+		subnet("subnet-1", "workers", "vpc-1"), subnet("subnet-2", "control-plane", "vpc-1"), subnet("subnet-3", "workers", "vpc-2"),
+	}}
+
+	tests := []struct {
+		name    string
+		subnet  *infrav1.IBMVPCResourceReference
+		vpcID   string
+		wantID  string
+		wantErr string
+	}{
+		{
+			name:   "uses the ID",
+			subnet: &infrav1.IBMVPCResourceReference{ID: core.StringPtr("subnet")},
+			wantID: "subnet",
+		},
+		{
+			name:   "restricts the name to the VPC",
+			subnet: &infrav1.IBMVPCResourceReference{Name: core.StringPtr("workers")},
+			vpcID:  "vpc-2",
+			wantID: "subnet-3",
+		},
+		{
+			name:    "fails on a name of subnets in several VPCs when the VPC is unknown",
+			subnet:  &infrav1.IBMVPCResourceReference{Name: core.StringPtr("workers")},
+			wantErr: "found 2 resources of type subnet with name workers, use the ID to reference it",
+		},
+		{
+			name:    "fails on a name of a subnet of another VPC",
+			subnet:  &infrav1.IBMVPCResourceReference{Name: core.StringPtr("control-plane")},
+			vpcID:   "vpc-2",
+			wantErr: "failed to find a subnet with name control-plane",
+		},
+		{
+			name:    "fails without subnet",
+			wantErr: "subnet of the primary network interface is not set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clients := newFakeVPCClients(t, subnets.ServeHTTP)
+			id, err := clients.getSubnetID(tt.subnet, tt.vpcID)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*id).To(Equal(tt.wantID))
+		})
+	}
+}
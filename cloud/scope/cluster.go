@@ -19,19 +19,24 @@ package scope
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/managementv2"
 	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
 
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
 )
 
 // ClusterScopeParams defines the input parameters used to create a new ClusterScope.
@@ -77,6 +82,14 @@ func NewClusterScope(params ClusterScopeParams, authenticator core.Authenticator
 		return nil, errors.Wrap(vpcErr, "failed to create IBM VPC session")
 	}
 
+	if err := params.IBMVPCClients.setGlobalTaggingService(authenticator); err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud Global Tagging session")
+	}
+
+	if err := params.IBMVPCClients.setIAMIdentityService(authenticator); err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud IAM Identity session")
+	}
+
 	return &ClusterScope{
 		Logger:        params.Logger,
 		client:        params.Client,
@@ -87,6 +100,68 @@ func NewClusterScope(params ClusterScopeParams, authenticator core.Authenticator
 	}, nil
 }
 
+// ValidateTrustedProfile checks that the trusted profile linked to the machines of the cluster exists.
+func (s *ClusterScope) ValidateTrustedProfile() error {
+	trustedProfileID := s.IBMVPCCluster.Spec.TrustedProfile
+	if trustedProfileID == "" {
+		return nil
+	}
+	profile, err := s.IAMClient.GetTrustedProfile(trustedProfileID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get trusted profile %s", trustedProfileID)
+	}
+	if profile == nil {
+		return fmt.Errorf("trusted profile %s not found", trustedProfileID)
+	}
+	return nil
+}
+
+// ReconcileResourceGroup resolves the resource group referenced in the spec and records its id in the status.
+func (s *ClusterScope) ReconcileResourceGroup() error {
+	id, err := s.resolveResourceGroupID()
+	if err != nil {
+		conditions.MarkFalse(s.IBMVPCCluster, infrav1.ResourceGroupResolvedCondition, infrav1.ResourceGroupNotResolvedReason, clusterv1.ConditionSeverityError,
+			"%s", err.Error())
+		return err
+	}
+	s.IBMVPCCluster.Status.ResourceGroupID = id
+	conditions.MarkTrue(s.IBMVPCCluster, infrav1.ResourceGroupResolvedCondition)
+	return nil
+}
+
+func (s *ClusterScope) resolveResourceGroupID() (string, error) {
+	if s.IBMVPCCluster.Spec.ResourceGroup != "" {
+		return s.IBMVPCCluster.Spec.ResourceGroup, nil
+	}
+	resourceGroup := s.IBMVPCCluster.Spec.ResourceGroupRef
+	if resourceGroup == nil {
+		return "", fmt.Errorf("neither resourceGroup nor resourceGroupRef is set")
+	} else if resourceGroup.ID != nil {
+		return *resourceGroup.ID, nil
+	} else if resourceGroup.Name == nil {
+		return "", fmt.Errorf("both ID and Name of the resource group can't be nil")
+	}
+
+	client, err := pkg.NewClient()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create IBM Cloud client")
+	}
+	groups, err := client.ResourceGroupClient.FindByName(&managementv2.ResourceGroupQuery{
+		AccountID: client.User.Account,
+	}, *resourceGroup.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find resource group %s", *resourceGroup.Name)
+	}
+	if len(groups) == 0 {
+		return "", fmt.Errorf("failed to find a resource group with name %s", *resourceGroup.Name)
+	}
+	if len(groups) > 1 {
+		return "", fmt.Errorf("found %d resource groups with name %s, use the ID to reference it", len(groups), *resourceGroup.Name)
+	}
+	s.Info("resource group found with ID", "ResourceGroup", *resourceGroup.Name, "ID", groups[0].ID)
+	return groups[0].ID, nil
+}
+
 // CreateVPC creates a new IBM VPC in specified resource group
 func (s *ClusterScope) CreateVPC() (*vpcv1.VPC, error) {
 	vpcReply, err := s.ensureVPCUnique(s.IBMVPCCluster.Spec.VPC)
@@ -99,7 +174,7 @@ func (s *ClusterScope) CreateVPC() (*vpcv1.VPC, error) {
 
 	options := &vpcv1.CreateVPCOptions{}
 	options.SetResourceGroup(&vpcv1.ResourceGroupIdentity{
-		ID: &s.IBMVPCCluster.Spec.ResourceGroup,
+		ID: &s.IBMVPCCluster.Status.ResourceGroupID,
 	})
 	options.SetName(s.IBMVPCCluster.Spec.VPC)
 	vpc, _, err := s.IBMVPCClients.VPCService.CreateVPC(options)
@@ -161,7 +236,7 @@ func (s *ClusterScope) ReserveFIP() (*vpcv1.FloatingIP, error) {
 	options.SetFloatingIPPrototype(&vpcv1.FloatingIPPrototype{
 		Name: &fipName,
 		ResourceGroup: &vpcv1.ResourceGroupIdentity{
-			ID: &s.IBMVPCCluster.Spec.ResourceGroup,
+			ID: &s.IBMVPCCluster.Status.ResourceGroupID,
 		},
 		Zone: &vpcv1.ZoneIdentity{
 			Name: &s.IBMVPCCluster.Spec.Zone,
@@ -339,6 +414,196 @@ func (s *ClusterScope) detachPublicGateway(subnetID string, pgwID string) error
 	return err
 }
 
+// CreateControlPlanePlacementGroup creates the placement group used to spread the control plane machines.
+func (s *ClusterScope) CreateControlPlanePlacementGroup() (*vpcv1.PlacementGroup, error) {
+	placementGroupName := s.IBMVPCCluster.Name + "-control-plane"
+	placementGroupReply, err := s.ensurePlacementGroupUnique(placementGroupName)
+	if err != nil {
+		return nil, err
+	} else if placementGroupReply != nil {
+		return placementGroupReply, nil
+	}
+
+	options := &vpcv1.CreatePlacementGroupOptions{}
+	options.SetName(placementGroupName)
+	options.SetStrategy(s.IBMVPCCluster.Spec.ControlPlanePlacementGroup.Strategy)
+	options.SetResourceGroup(&vpcv1.ResourceGroupIdentity{
+		ID: &s.IBMVPCCluster.Status.ResourceGroupID,
+	})
+	placementGroup, _, err := s.IBMVPCClients.VPCService.CreatePlacementGroup(options)
+	return placementGroup, err
+}
+
+func (s *ClusterScope) ensurePlacementGroupUnique(placementGroupName string) (*vpcv1.PlacementGroup, error) {
+	options := &vpcv1.ListPlacementGroupsOptions{}
+	for {
+		placementGroups, _, err := s.IBMVPCClients.VPCService.ListPlacementGroups(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, placementGroup := range placementGroups.PlacementGroups {
+			if *placementGroup.Name == placementGroupName {
+				return &placementGroup, nil
+			}
+		}
+		start, err := placementGroups.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			return nil, nil
+		}
+		options.SetStart(*start)
+	}
+}
+
+// DeleteControlPlanePlacementGroup deletes the placement group created for the control plane machines.
+func (s *ClusterScope) DeleteControlPlanePlacementGroup() error {
+	placementGroupID := s.IBMVPCCluster.Status.ControlPlanePlacementGroupID
+	if placementGroupID == "" {
+		return nil
+	}
+	options := &vpcv1.DeletePlacementGroupOptions{}
+	options.SetID(placementGroupID)
+	response, err := s.IBMVPCClients.VPCService.DeletePlacementGroup(options)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	return nil
+}
+
+// bootstrapDataStore returns the store of the bootstrap data in the given bucket, or nil if the cluster delivers
+// it in the user data. The bucket defaults to the one of the spec.
+func (s *ClusterScope) bootstrapDataStore(bucket string) (*bootstrapDataStore, error) {
+	if s.IBMVPCCluster.Spec.BootstrapStorage == nil {
+		return nil, nil
+	}
+	storage := &v1alpha4.BootstrapStorage{}
+	if err := infrav1.Convert_v1alpha3_BootstrapStorage_To_v1alpha4_BootstrapStorage(s.IBMVPCCluster.Spec.BootstrapStorage, storage, nil); err != nil {
+		return nil, err
+	}
+	if bucket != "" {
+		storage.Bucket = bucket
+	}
+	return newBootstrapDataStore(storage, s.Cluster.Name)
+}
+
+// ReconcileBootstrapBucket creates the bucket of the bootstrap data when the cluster delivers it through Cloud Object
+// Storage, a bucket created by the controller is deleted with the cluster.
+func (s *ClusterScope) ReconcileBootstrapBucket() error {
+	store, err := s.bootstrapDataStore("")
+	if err != nil || store == nil {
+		return err
+	}
+	if status := s.IBMVPCCluster.Status.BootstrapBucket; status != nil && status.Name == store.bucket {
+		return nil
+	}
+
+	created, err := store.EnsureBucket()
+	if err != nil {
+		return errors.Wrapf(err, "failed to ensure bootstrap data bucket %s", store.bucket)
+	}
+	if created {
+		s.Info("Created bootstrap data bucket", "name", store.bucket)
+	}
+	s.IBMVPCCluster.Status.BootstrapBucket = &infrav1.BootstrapBucketStatus{
+		Name:              store.bucket,
+		ControllerCreated: created,
+	}
+	return nil
+}
+
+// DeleteBootstrapBucket deletes the bucket of the bootstrap data if it was created by the controller, the machines
+// have deleted their bootstrap data by the time the cluster is deleted.
+func (s *ClusterScope) DeleteBootstrapBucket() error {
+	status := s.IBMVPCCluster.Status.BootstrapBucket
+	if status == nil {
+		return nil
+	}
+	if status.ControllerCreated {
+		store, err := s.bootstrapDataStore(status.Name)
+		if err != nil {
+			return err
+		} else if store != nil {
+			if err := store.DeleteBucket(); err != nil {
+				return errors.Wrapf(err, "failed to delete bootstrap data bucket %s", status.Name)
+			}
+			s.Info("Deleted bootstrap data bucket", "name", status.Name)
+		}
+	}
+	s.IBMVPCCluster.Status.BootstrapBucket = nil
+	return nil
+}
+
+// ReconcileTags attaches the cluster tags to the VPC, subnet, public gateway, floating IP and placement group
+// of the cluster. The resources are only tagged when the tags or the resources changed since the last
+// reconciliation, re-attaching the tags detached in the meantime. Tags removed from the spec are not detached.
+func (s *ClusterScope) ReconcileTags() error {
+	status := &s.IBMVPCCluster.Status
+	var ids []string
+	for _, id := range []*string{&status.VPC.ID, status.Subnet.ID, status.APIEndpoint.FIPID, &status.ControlPlanePlacementGroupID} {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	hash, err := tagsHash(ids, clusterTags(s.Cluster, TagRoleCommon, s.IBMVPCCluster.Spec.Tags), s.IBMVPCCluster.Spec.AccessTags)
+	if err != nil {
+		return err
+	}
+	if status.TagsHash == hash {
+		return nil
+	}
+
+	crns := map[string]string{}
+	if s.IBMVPCCluster.Status.VPC.ID != "" {
+		vpc, _, err := s.IBMVPCClients.VPCService.GetVPC(&vpcv1.GetVPCOptions{
+			ID: &s.IBMVPCCluster.Status.VPC.ID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get VPC %s", s.IBMVPCCluster.Status.VPC.ID)
+		}
+		crns[*vpc.CRN] = TagRoleCommon
+	}
+	if s.IBMVPCCluster.Status.Subnet.ID != nil {
+		subnet, _, err := s.IBMVPCClients.VPCService.GetSubnet(&vpcv1.GetSubnetOptions{
+			ID: s.IBMVPCCluster.Status.Subnet.ID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get subnet %s", *s.IBMVPCCluster.Status.Subnet.ID)
+		}
+		crns[*subnet.CRN] = TagRoleCommon
+		if subnet.PublicGateway != nil {
+			crns[*subnet.PublicGateway.CRN] = TagRoleCommon
+		}
+	}
+	if s.IBMVPCCluster.Status.APIEndpoint.FIPID != nil {
+		fip, _, err := s.IBMVPCClients.VPCService.GetFloatingIP(&vpcv1.GetFloatingIPOptions{
+			ID: s.IBMVPCCluster.Status.APIEndpoint.FIPID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get floating IP %s", *s.IBMVPCCluster.Status.APIEndpoint.FIPID)
+		}
+		crns[*fip.CRN] = TagRoleAPIServer
+	}
+	if s.IBMVPCCluster.Status.ControlPlanePlacementGroupID != "" {
+		placementGroup, _, err := s.IBMVPCClients.VPCService.GetPlacementGroup(&vpcv1.GetPlacementGroupOptions{
+			ID: &s.IBMVPCCluster.Status.ControlPlanePlacementGroupID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get placement group %s", s.IBMVPCCluster.Status.ControlPlanePlacementGroupID)
+		}
+		crns[*placementGroup.CRN] = TagRoleControlPlane
+	}
+
+	for crn, role := range crns {
+		tags := clusterTags(s.Cluster, role, s.IBMVPCCluster.Spec.Tags)
+		if err := reconcileTags(s.IBMVPCClients.TaggingClient, crn, tags, s.IBMVPCCluster.Spec.AccessTags); err != nil {
+			return err
+		}
+	}
+	status.TagsHash = hash
+	return nil
+}
+
 // PatchObject persists the cluster configuration and status.
 func (s *ClusterScope) PatchObject() error {
 	return s.patchHelper.Patch(context.TODO(), s.IBMVPCCluster)
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// metadataScriptPath is the path of the script fetching the VPC instance metadata.
+	metadataScriptPath = "/opt/ibmcloud/bin/fetch-instance-metadata"
+
+	// metadataScript fetches the instance metadata from the VPC metadata service into /run/metadata/ibmcloud-instance.json.
+	metadataScript = `#!/bin/sh
+set -e
+endpoint=http://169.254.169.254
+version=2022-03-01
+token=$(curl -sSf -X PUT "${endpoint}/instance_identity/v1/token?version=${version}" \
+  -H "Metadata-Flavor: ibm" -H "Content-Type: application/json" -d '{}' \
+  | sed -n 's/.*"access_token" *: *"\([^"]*\)".*/\1/p')
+mkdir -p /run/metadata
+curl -sSf "${endpoint}/metadata/v1/instance?version=${version}" \
+  -H "Authorization: Bearer ${token}" -o /run/metadata/ibmcloud-instance.json
+`
+
+	metadataUnitName = "ibmcloud-instance-metadata.service"
+
+	metadataUnit = `[Unit]
+Description=Fetch the IBM Cloud VPC instance metadata
+Wants=network-online.target
+After=network-online.target
+Before=kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + metadataScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+)
+
+// ignitionOptions are the provider generated parts added to the Ignition config of an instance.
+type ignitionOptions struct {
+	// Hostname is written to /etc/hostname when not empty.
+	Hostname string
+	// FetchMetadata adds a unit fetching the instance metadata from the VPC metadata service.
+	FetchMetadata bool
+}
+
+// ignitionUserData returns an Ignition config merging the bootstrap Ignition config with the provider generated
+// files and units. The bootstrap config is fetched from source when it is not empty, or embedded otherwise.
+// The config uses the version of the bootstrap config, both Ignition spec 2.x and 3.x are supported.
+func ignitionUserData(bootstrapData []byte, source string, options ignitionOptions) ([]byte, error) {
+	var bootstrap struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(bootstrapData, &bootstrap); err != nil {
+		return nil, errors.Wrap(err, "bootstrap data is not a valid Ignition config")
+	}
+	version := bootstrap.Ignition.Version
+	if source == "" {
+		source = ignitionDataURL(bootstrapData)
+	}
+
+	var files, units []map[string]interface{}
+	if options.Hostname != "" {
+		files = append(files, ignitionFile("/etc/hostname", 0644, options.Hostname+"\n"))
+	}
+	if options.FetchMetadata {
+		files = append(files, ignitionFile(metadataScriptPath, 0755, metadataScript))
+		units = append(units, map[string]interface{}{
+			"name":     metadataUnitName,
+			"enabled":  true,
+			"contents": metadataUnit,
+		})
+	}
+
+	config := map[string]interface{}{}
+	switch {
+	case strings.HasPrefix(version, "2."):
+		config["ignition"] = map[string]interface{}{
+			"version": version,
+			"config": map[string]interface{}{
+				"append": []map[string]string{{"source": source}},
+			},
+		}
+		for _, file := range files {
+			file["filesystem"] = "root"
+		}
+	case strings.HasPrefix(version, "3."):
+		config["ignition"] = map[string]interface{}{
+			"version": version,
+			"config": map[string]interface{}{
+				"merge": []map[string]string{{"source": source}},
+			},
+		}
+		for _, file := range files {
+			file["overwrite"] = true
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Ignition config version %q", version)
+	}
+	if len(files) > 0 {
+		config["storage"] = map[string]interface{}{"files": files}
+	}
+	if len(units) > 0 {
+		config["systemd"] = map[string]interface{}{"units": units}
+	}
+	return json.Marshal(config)
+}
+
+func ignitionFile(path string, mode int, contents string) map[string]interface{} {
+	return map[string]interface{}{
+		"path": path,
+		"mode": mode,
+		"contents": map[string]string{
+			"source": ignitionDataURL([]byte(contents)),
+		},
+	}
+}
+
+func ignitionDataURL(data []byte) string {
+	return "data:;base64," + base64.StdEncoding.EncodeToString(data)
+}
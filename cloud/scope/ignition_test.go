@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIgnitionUserData(t *testing.T) {
+	// The provider generated metadata script and unit are substituted in the expected configs.
+	script, err := json.Marshal(ignitionDataURL([]byte(metadataScript)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit, err := json.Marshal(metadataUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacer := strings.NewReplacer("$SCRIPT", string(script), "$UNIT", string(unit))
+
+	tests := []struct {
+		name          string
+		bootstrapData string
+		source        string
+		options       ignitionOptions
+		want          string
+		wantErr       string
+	}{
+		{
+			name:          "appends the embedded spec 2 config",
+			bootstrapData: `{"ignition":{"version":"2.3.0"}}`,
+			options:       ignitionOptions{Hostname: "machine"},
+			want: `{
+				"ignition": {
+					"version": "2.3.0",
+					"config": {"append": [{"source": "data:;base64,eyJpZ25pdGlvbiI6eyJ2ZXJzaW9uIjoiMi4zLjAifX0="}]}
+				},
+				"storage": {"files": [
+					{"filesystem": "root", "path": "/etc/hostname", "mode": 420, "contents": {"source": "data:;base64,bWFjaGluZQo="}}
+				]}
+			}`,
+		},
+		{
+			name:          "merges the embedded spec 3 config",
+			bootstrapData: `{"ignition":{"version":"3.2.0"}}`,
+			options:       ignitionOptions{Hostname: "machine"},
+			want: `{
+				"ignition": {
+					"version": "3.2.0",
+					"config": {"merge": [{"source": "data:;base64,eyJpZ25pdGlvbiI6eyJ2ZXJzaW9uIjoiMy4yLjAifX0="}]}
+				},
+				"storage": {"files": [
+					{"overwrite": true, "path": "/etc/hostname", "mode": 420, "contents": {"source": "data:;base64,bWFjaGluZQo="}}
+				]}
+			}`,
+		},
+		{
+			name:          "fetches the spec 2 config and the instance metadata",
+			bootstrapData: `{"ignition":{"version":"2.3.0"}}`,
+			source:        "https://cos.example/bucket/default/machine?X-Amz-Signature=signature",
+			options:       ignitionOptions{Hostname: "machine", FetchMetadata: true},
+			want: `{
+				"ignition": {
+					"version": "2.3.0",
+					"config": {"append": [{"source": "https://cos.example/bucket/default/machine?X-Amz-Signature=signature"}]}
+				},
+				"storage": {"files": [
+					{"filesystem": "root", "path": "/etc/hostname", "mode": 420, "contents": {"source": "data:;base64,bWFjaGluZQo="}},
+					{"filesystem": "root", "path": "/opt/ibmcloud/bin/fetch-instance-metadata", "mode": 493, "contents": {"source": $SCRIPT}}
+				]},
+				"systemd": {"units": [
+					{"name": "ibmcloud-instance-metadata.service", "enabled": true, "contents": $UNIT}
+				]}
+			}`,
+		},
+		{
+			name:          "fetches the spec 3 config without provider generated parts",
+			bootstrapData: `{"ignition":{"version":"3.2.0"}}`,
+			source:        "https://cos.example/bucket/default/machine?X-Amz-Signature=signature",
+			want: `{
+				"ignition": {
+					"version": "3.2.0",
+					"config": {"merge": [{"source": "https://cos.example/bucket/default/machine?X-Amz-Signature=signature"}]}
+				}
+			}`,
+		},
+		{
+			name:          "rejects an unsupported spec version",
+			bootstrapData: `{"ignition":{"version":"1.0.0"}}`,
+			wantErr:       `unsupported Ignition config version "1.0.0"`,
+		},
+		{
+			name:          "rejects bootstrap data which is not an Ignition config",
+			bootstrapData: "#cloud-config\n",
+			wantErr:       "bootstrap data is not a valid Ignition config",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			userData, err := ignitionUserData([]byte(tt.bootstrapData), tt.source, tt.options)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(userData).To(MatchJSON(replacer.Replace(tt.want)))
+		})
+	}
+}
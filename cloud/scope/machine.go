@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -30,10 +31,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
 )
 
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
@@ -84,6 +87,14 @@ func NewMachineScope(params MachineScopeParams, authenticator core.Authenticator
 		return nil, errors.Wrap(vpcErr, "failed to create IBM VPC session")
 	}
 
+	if err := params.IBMVPCClients.setGlobalTaggingService(authenticator); err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud Global Tagging session")
+	}
+
+	if err := params.IBMVPCClients.setIAMIdentityService(authenticator); err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud IAM Identity session")
+	}
+
 	return &MachineScope{
 		Logger:        params.Logger,
 		client:        params.Client,
@@ -111,11 +122,39 @@ func (m *MachineScope) CreateMachine() (*vpcv1.Instance, error) {
 		return nil, err
 	}
 
+	imageID, err := m.getImageID(m.imageReference())
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting image ID")
+	}
+
+	vpcID := ""
+	if m.IBMVPCCluster != nil {
+		vpcID = m.IBMVPCCluster.Status.VPC.ID
+	}
+	subnetID, err := m.getSubnetID(m.subnetReference(), vpcID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting subnet ID")
+	}
+
+	sshKeyIDs, err := m.getSSHKeyIDs(m.sshKeyReferences())
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting SSH key IDs")
+	}
+
+	placementTarget, err := m.getPlacementTarget()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting placement target")
+	}
+
+	m.IBMVPCMachine.Status.ImageID = *imageID
+	m.IBMVPCMachine.Status.SubnetID = *subnetID
+	m.IBMVPCMachine.Status.SSHKeyIDs = sshKeyIDs
+
 	options := &vpcv1.CreateInstanceOptions{}
 	instancePrototype := &vpcv1.InstancePrototype{
 		Name: &m.IBMVPCMachine.Name,
 		Image: &vpcv1.ImageIdentity{
-			ID: &m.IBMVPCMachine.Spec.Image,
+			ID: imageID,
 		},
 		Profile: &vpcv1.InstanceProfileIdentity{
 			Name: &m.IBMVPCMachine.Spec.Profile,
@@ -125,30 +164,388 @@ func (m *MachineScope) CreateMachine() (*vpcv1.Instance, error) {
 		},
 		PrimaryNetworkInterface: &vpcv1.NetworkInterfacePrototype{
 			Subnet: &vpcv1.SubnetIdentity{
-				ID: &m.IBMVPCMachine.Spec.PrimaryNetworkInterface.Subnet,
+				ID: subnetID,
 			},
 		},
 		UserData: &cloudInitData,
 	}
 
-	if m.IBMVPCMachine.Spec.SSHKeys != nil {
+	if sshKeyIDs != nil {
 		instancePrototype.Keys = []vpcv1.KeyIdentityIntf{}
-		for _, sshKey := range m.IBMVPCMachine.Spec.SSHKeys {
+		for i := range sshKeyIDs {
 			key := &vpcv1.KeyIdentity{
-				ID: sshKey,
+				ID: &sshKeyIDs[i],
 			}
 			instancePrototype.Keys = append(instancePrototype.Keys, key)
 		}
 
 	}
 
+	if placementTarget != nil {
+		instancePrototype.PlacementTarget = placementTarget
+	}
+
+	if len(m.IBMVPCMachine.Spec.DataVolumes) > 0 {
+		instancePrototype.VolumeAttachments = m.getVolumeAttachmentPrototypes()
+	}
+
+	if trustedProfileID := m.trustedProfileID(); trustedProfileID != "" || m.IBMVPCMachine.Spec.MetadataService {
+		return m.createInstance(instancePrototype, trustedProfileID)
+	}
+
 	options.SetInstancePrototype(instancePrototype)
-	instance, response, err := m.IBMVPCClients.VPCService.CreateInstance(options)
-	fmt.Printf("%v\n", response)
+	instance, _, err := m.IBMVPCClients.VPCService.CreateInstance(options)
 	return instance, err
 }
 
+// imageReference returns the reference to the image of the instance, the image id takes precedence over the reference.
+func (m *MachineScope) imageReference() infrav1.IBMVPCResourceReference {
+	if m.IBMVPCMachine.Spec.Image != "" {
+		return infrav1.IBMVPCResourceReference{ID: &m.IBMVPCMachine.Spec.Image}
+	} else if m.IBMVPCMachine.Spec.ImageRef == nil {
+		return infrav1.IBMVPCResourceReference{}
+	}
+	return *m.IBMVPCMachine.Spec.ImageRef
+}
+
+// subnetReference returns the reference to the subnet of the primary network interface, the subnet id takes
+// precedence over the reference.
+func (m *MachineScope) subnetReference() *infrav1.IBMVPCResourceReference {
+	networkInterface := m.IBMVPCMachine.Spec.PrimaryNetworkInterface
+	if networkInterface.Subnet != "" {
+		return &infrav1.IBMVPCResourceReference{ID: &networkInterface.Subnet}
+	}
+	return networkInterface.SubnetRef
+}
+
+// sshKeyReferences returns the references to the SSH keys of the instance, the SSH key ids followed by the references.
+func (m *MachineScope) sshKeyReferences() []*infrav1.IBMVPCResourceReference {
+	var references []*infrav1.IBMVPCResourceReference
+	for _, id := range m.IBMVPCMachine.Spec.SSHKeys {
+		if id != nil {
+			references = append(references, &infrav1.IBMVPCResourceReference{ID: id})
+		}
+	}
+	return append(references, m.IBMVPCMachine.Spec.SSHKeyRefs...)
+}
+
+// getPlacementTarget returns the placement target of the instance, only one of placement group,
+// dedicated host or dedicated host group can be referenced.
+func (m *MachineScope) getPlacementTarget() (vpcv1.InstancePlacementTargetPrototypeIntf, error) {
+	spec := m.IBMVPCMachine.Spec
+	targets := 0
+	for _, target := range []*infrav1.IBMVPCResourceReference{spec.PlacementGroup, spec.DedicatedHost, spec.DedicatedHostGroup} {
+		if target != nil {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return nil, fmt.Errorf("only one of placementGroup, dedicatedHost and dedicatedHostGroup can be specified")
+	}
+
+	switch {
+	case spec.DedicatedHost != nil:
+		host, err := m.getDedicatedHost(spec.DedicatedHost)
+		if err != nil {
+			return nil, err
+		}
+		if host.InstancePlacementEnabled == nil || !*host.InstancePlacementEnabled {
+			return nil, fmt.Errorf("instance placement is disabled on dedicated host %s", *host.Name)
+		}
+		if err := m.validateDedicatedHostPlacement("dedicated host", *host.Name, host.Zone, host.SupportedInstanceProfiles); err != nil {
+			return nil, err
+		}
+		return &vpcv1.InstancePlacementTargetPrototypeDedicatedHostIdentity{
+			ID: host.ID,
+		}, nil
+	case spec.DedicatedHostGroup != nil:
+		group, err := m.getDedicatedHostGroup(spec.DedicatedHostGroup)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.validateDedicatedHostPlacement("dedicated host group", *group.Name, group.Zone, group.SupportedInstanceProfiles); err != nil {
+			return nil, err
+		}
+		profile, _, err := m.IBMVPCClients.VPCService.GetInstanceProfile(&vpcv1.GetInstanceProfileOptions{
+			Name: &spec.Profile,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if profile.Family != nil && *profile.Family != *group.Family {
+			return nil, fmt.Errorf("instance profile %s of family %s can't be placed in dedicated host group %s of family %s", spec.Profile, *profile.Family, *group.Name, *group.Family)
+		}
+		return &vpcv1.InstancePlacementTargetPrototypeDedicatedHostGroupIdentity{
+			ID: group.ID,
+		}, nil
+	}
+
+	placementGroupID, err := m.getPlacementGroupID()
+	if err != nil || placementGroupID == nil {
+		return nil, err
+	}
+	m.IBMVPCMachine.Status.PlacementGroupID = *placementGroupID
+	return &vpcv1.InstancePlacementTargetPrototypePlacementGroupIdentity{
+		ID: placementGroupID,
+	}, nil
+}
+
+func (m *MachineScope) getDedicatedHost(dedicatedHost *infrav1.IBMVPCResourceReference) (*vpcv1.DedicatedHost, error) {
+	if dedicatedHost.ID != nil {
+		host, _, err := m.IBMVPCClients.VPCService.GetDedicatedHost(&vpcv1.GetDedicatedHostOptions{
+			ID: dedicatedHost.ID,
+		})
+		return host, err
+	} else if dedicatedHost.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+
+	var hosts []vpcv1.DedicatedHost
+	options := &vpcv1.ListDedicatedHostsOptions{}
+	options.SetName(*dedicatedHost.Name)
+	for {
+		collection, _, err := m.IBMVPCClients.VPCService.ListDedicatedHosts(options)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, collection.DedicatedHosts...)
+		start, err := collection.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+
+	var ids []string
+	for _, host := range hosts {
+		ids = append(ids, *host.ID)
+	}
+	if _, err := uniqueResourceID("dedicated host", *dedicatedHost.Name, ids); err != nil {
+		return nil, err
+	}
+	return &hosts[0], nil
+}
+
+func (m *MachineScope) getDedicatedHostGroup(dedicatedHostGroup *infrav1.IBMVPCResourceReference) (*vpcv1.DedicatedHostGroup, error) {
+	if dedicatedHostGroup.ID != nil {
+		group, _, err := m.IBMVPCClients.VPCService.GetDedicatedHostGroup(&vpcv1.GetDedicatedHostGroupOptions{
+			ID: dedicatedHostGroup.ID,
+		})
+		return group, err
+	} else if dedicatedHostGroup.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+
+	var groups []vpcv1.DedicatedHostGroup
+	options := &vpcv1.ListDedicatedHostGroupsOptions{}
+	options.SetName(*dedicatedHostGroup.Name)
+	for {
+		collection, _, err := m.IBMVPCClients.VPCService.ListDedicatedHostGroups(options)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, collection.Groups...)
+		start, err := collection.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+
+	var ids []string
+	for _, group := range groups {
+		ids = append(ids, *group.ID)
+	}
+	if _, err := uniqueResourceID("dedicated host group", *dedicatedHostGroup.Name, ids); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// validateDedicatedHostPlacement checks that the dedicated host or group is in the zone of the machine and supports its profile.
+func (m *MachineScope) validateDedicatedHostPlacement(kind, name string, zone *vpcv1.ZoneReference, profiles []vpcv1.InstanceProfileReference) error {
+	spec := m.IBMVPCMachine.Spec
+	if zone == nil || *zone.Name != spec.Zone {
+		return fmt.Errorf("%s %s is not in zone %s", kind, name, spec.Zone)
+	}
+	for _, profile := range profiles {
+		if *profile.Name == spec.Profile {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s does not support instance profile %s", kind, name, spec.Profile)
+}
+
+// getPlacementGroupID resolves the placement group of the instance, control plane machines
+// default to the placement group of the cluster control plane.
+func (m *MachineScope) getPlacementGroupID() (*string, error) {
+	placementGroup := m.IBMVPCMachine.Spec.PlacementGroup
+	if placementGroup == nil {
+		if _, ok := m.IBMVPCMachine.Labels[clusterv1.MachineControlPlaneLabelName]; ok && m.IBMVPCCluster != nil && m.IBMVPCCluster.Status.ControlPlanePlacementGroupID != "" {
+			return &m.IBMVPCCluster.Status.ControlPlanePlacementGroupID, nil
+		}
+		return nil, nil
+	} else if placementGroup.ID != nil {
+		return placementGroup.ID, nil
+	} else if placementGroup.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+
+	var ids []string
+	options := &vpcv1.ListPlacementGroupsOptions{}
+	for {
+		placementGroups, _, err := m.IBMVPCClients.VPCService.ListPlacementGroups(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, pg := range placementGroups.PlacementGroups {
+			if *pg.Name == *placementGroup.Name {
+				ids = append(ids, *pg.ID)
+			}
+		}
+		start, err := placementGroups.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+	return uniqueResourceID("placement group", *placementGroup.Name, ids)
+}
+
+// ReconcilePlacement reports in the PlacementReady condition whether the instance is placed in its placement group.
+func (m *MachineScope) ReconcilePlacement(instance *vpcv1.Instance) error {
+	placementGroupID := m.IBMVPCMachine.Status.PlacementGroupID
+	if placementGroupID == "" {
+		return nil
+	}
+
+	target, ok := instance.PlacementTarget.(*vpcv1.InstancePlacementTarget)
+	if !ok || target.ID == nil || *target.ID != placementGroupID {
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.PlacementReadyCondition, infrav1.PlacementViolatedReason, clusterv1.ConditionSeverityError,
+			"instance %s is not placed in placement group %s", *instance.ID, placementGroupID)
+		return nil
+	}
+
+	options := &vpcv1.GetPlacementGroupOptions{}
+	options.SetID(placementGroupID)
+	placementGroup, _, err := m.IBMVPCClients.VPCService.GetPlacementGroup(options)
+	if err != nil {
+		return err
+	}
+	if *placementGroup.LifecycleState != vpcv1.PlacementGroupLifecycleStateStableConst {
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.PlacementReadyCondition, infrav1.PlacementGroupNotStableReason, clusterv1.ConditionSeverityWarning,
+			"placement group %s is in %s state", *placementGroup.Name, *placementGroup.LifecycleState)
+		return nil
+	}
+	conditions.MarkTrue(m.IBMVPCMachine, infrav1.PlacementReadyCondition)
+	return nil
+}
+
+// ReconcileTags attaches the cluster and machine tags to the instance and its volumes. The resources are only tagged
+// when the tags or the volumes changed since the last reconciliation, re-attaching the tags detached in the meantime.
+// Tags removed from the spec are not detached.
+func (m *MachineScope) ReconcileTags(instance *vpcv1.Instance) error {
+	var clusterUserTags, clusterAccessTags []string
+	if m.IBMVPCCluster != nil {
+		clusterUserTags = m.IBMVPCCluster.Spec.Tags
+		clusterAccessTags = m.IBMVPCCluster.Spec.AccessTags
+	}
+	tags := clusterTags(m.Cluster, machineRole(m.Machine), clusterUserTags, m.IBMVPCMachine.Spec.Tags)
+	accessTags := append(append([]string{}, clusterAccessTags...), m.IBMVPCMachine.Spec.AccessTags...)
+
+	crns := []string{*instance.CRN}
+	for _, attachment := range instance.VolumeAttachments {
+		if attachment.Volume != nil && attachment.Volume.CRN != nil {
+			crns = append(crns, *attachment.Volume.CRN)
+		}
+	}
+	hash, err := tagsHash(crns, tags, accessTags)
+	if err != nil {
+		return err
+	}
+	if m.IBMVPCMachine.Status.TagsHash == hash {
+		return nil
+	}
+	for _, crn := range crns {
+		if err := reconcileTags(m.IBMVPCClients.TaggingClient, crn, tags, accessTags); err != nil {
+			return err
+		}
+	}
+	m.IBMVPCMachine.Status.TagsHash = hash
+	return nil
+}
+
+func (m *MachineScope) getVolumeAttachmentPrototypes() []vpcv1.VolumeAttachmentPrototypeInstanceContext {
+	attachments := []vpcv1.VolumeAttachmentPrototypeInstanceContext{}
+	for i, volume := range m.IBMVPCMachine.Spec.DataVolumes {
+		name := m.dataVolumeName(i, volume)
+		profile := volume.Profile
+		if profile == "" {
+			profile = "general-purpose"
+		}
+		prototype := &vpcv1.VolumeAttachmentVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumeByCapacity{
+			Name:     core.StringPtr(name),
+			Capacity: core.Int64Ptr(volume.SizeGiB),
+			Profile: &vpcv1.VolumeProfileIdentityByName{
+				Name: core.StringPtr(profile),
+			},
+		}
+		if volume.Iops != 0 {
+			prototype.Iops = core.Int64Ptr(volume.Iops)
+		}
+		if volume.EncryptionKeyCRN != "" {
+			prototype.EncryptionKey = &vpcv1.EncryptionKeyIdentityByCRN{
+				CRN: core.StringPtr(volume.EncryptionKeyCRN),
+			}
+		}
+		attachments = append(attachments, vpcv1.VolumeAttachmentPrototypeInstanceContext{
+			Name:                         core.StringPtr(name),
+			DeleteVolumeOnInstanceDelete: core.BoolPtr(deleteOnInstanceDelete(volume)),
+			Volume:                       prototype,
+		})
+	}
+	return attachments
+}
+
+func (m *MachineScope) dataVolumeName(index int, volume infrav1.VPCVolume) string {
+	if volume.Name != "" {
+		return fmt.Sprintf("%s-%s", m.IBMVPCMachine.Name, volume.Name)
+	}
+	return fmt.Sprintf("%s-data-%d", m.IBMVPCMachine.Name, index)
+}
+
+func deleteOnInstanceDelete(volume infrav1.VPCVolume) bool {
+	return volume.DeleteOnInstanceDelete == nil || *volume.DeleteOnInstanceDelete
+}
+
+// GetDataVolumesStatus returns the status of the data volumes attached to the instance.
+func (m *MachineScope) GetDataVolumesStatus(instance *vpcv1.Instance) []infrav1.VPCVolumeStatus {
+	var volumes []infrav1.VPCVolumeStatus
+	for _, attachment := range instance.VolumeAttachments {
+		if instance.BootVolumeAttachment != nil && *attachment.ID == *instance.BootVolumeAttachment.ID {
+			continue
+		}
+		if attachment.Volume == nil {
+			continue
+		}
+		volumes = append(volumes, infrav1.VPCVolumeStatus{
+			Name:         *attachment.Volume.Name,
+			ID:           *attachment.Volume.ID,
+			AttachmentID: *attachment.ID,
+		})
+	}
+	return volumes
+}
+
 // DeleteMachine deletes the vpc machine associated with machine instance id.
+// The data volumes which should not outlive the instance are deleted by the cloud along with it,
+// as their attachments are created with the delete policy from the spec.
 func (m *MachineScope) DeleteMachine() error {
 	options := &vpcv1.DeleteInstanceOptions{}
 	options.SetID(m.IBMVPCMachine.Status.InstanceID)
@@ -171,15 +568,109 @@ func (m *MachineScope) ensureInstanceUnique(instanceName string) (*vpcv1.Instanc
 	return nil, nil
 }
 
-// GetMachine returns a machine associated with a machine instanceID
+// GetMachine returns a machine associated with a machine instanceID, or nil if the instance does not exist.
 func (m *MachineScope) GetMachine(instanceID string) (*vpcv1.Instance, error) {
 	options := &vpcv1.GetInstanceOptions{}
 	options.SetID(instanceID)
 
-	instance, _, err := m.IBMVPCClients.VPCService.GetInstance(options)
+	instance, response, err := m.IBMVPCClients.VPCService.GetInstance(options)
+	if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 	return instance, err
 }
 
+// ValidateProfile checks that the instance profile of the machine exists in the region and records the result
+// in the InstanceProfileValid condition.
+func (m *MachineScope) ValidateProfile() (bool, error) {
+	profileName := m.IBMVPCMachine.Spec.Profile
+	profile, err := m.getInstanceProfile(profileName)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get instance profile %s", profileName)
+	}
+	if profile == nil {
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceProfileValidCondition, infrav1.InstanceProfileNotFoundReason, clusterv1.ConditionSeverityError,
+			"instance profile %s not found", profileName)
+		return false, nil
+	}
+	conditions.MarkTrue(m.IBMVPCMachine, infrav1.InstanceProfileValidCondition)
+	return true, nil
+}
+
+// trustedProfileID returns the ID of the trusted profile linked to the instance, the trusted profile of the machine
+// takes precedence over the one of the cluster.
+func (m *MachineScope) trustedProfileID() string {
+	if m.IBMVPCMachine.Spec.TrustedProfile != "" {
+		return m.IBMVPCMachine.Spec.TrustedProfile
+	} else if m.IBMVPCCluster != nil {
+		return m.IBMVPCCluster.Spec.TrustedProfile
+	}
+	return ""
+}
+
+// ValidateTrustedProfile checks that the trusted profile linked to the instance exists and records the result
+// in the TrustedProfileValid condition.
+func (m *MachineScope) ValidateTrustedProfile() (bool, error) {
+	trustedProfileID := m.trustedProfileID()
+	if trustedProfileID == "" {
+		return true, nil
+	}
+	profile, err := m.IAMClient.GetTrustedProfile(trustedProfileID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get trusted profile %s", trustedProfileID)
+	}
+	if profile == nil {
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.TrustedProfileValidCondition, infrav1.TrustedProfileNotFoundReason, clusterv1.ConditionSeverityError,
+			"trusted profile %s not found", trustedProfileID)
+		return false, nil
+	}
+	conditions.MarkTrue(m.IBMVPCMachine, infrav1.TrustedProfileValidCondition)
+	return true, nil
+}
+
+// SetInstanceState records the lifecycle state of the instance in the status and the InstanceReady condition,
+// the machine is only ready while the instance is running.
+func (m *MachineScope) SetInstanceState(instance *vpcv1.Instance) {
+	state := *instance.Status
+	m.IBMVPCMachine.Status.InstanceStatus = state
+	m.IBMVPCMachine.Status.Ready = state == vpcv1.InstanceStatusRunningConst
+
+	switch state {
+	case vpcv1.InstanceStatusRunningConst:
+		conditions.MarkTrue(m.IBMVPCMachine, infrav1.InstanceReadyCondition)
+	case vpcv1.InstanceStatusPendingConst, vpcv1.InstanceStatusStartingConst, vpcv1.InstanceStatusRestartingConst, vpcv1.InstanceStatusResumingConst:
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceReadyCondition, infrav1.InstancePendingReason, clusterv1.ConditionSeverityInfo,
+			"instance %s is %s", *instance.ID, state)
+	case vpcv1.InstanceStatusStoppedConst, vpcv1.InstanceStatusStoppingConst, vpcv1.InstanceStatusPausedConst, vpcv1.InstanceStatusPausingConst:
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStoppedReason, clusterv1.ConditionSeverityWarning,
+			"instance %s is %s", *instance.ID, state)
+	case vpcv1.InstanceStatusFailedConst:
+		message := fmt.Sprintf("instance %s is failed", *instance.ID)
+		for _, reason := range instance.StatusReasons {
+			message = fmt.Sprintf("%s: %s", message, *reason.Message)
+		}
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceReadyCondition, infrav1.InstanceFailedReason, clusterv1.ConditionSeverityError, message)
+	case vpcv1.InstanceStatusDeletingConst:
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceReadyCondition, infrav1.InstanceDeletingReason, clusterv1.ConditionSeverityWarning,
+			"instance %s is being deleted", *instance.ID)
+	default:
+		conditions.MarkFalse(m.IBMVPCMachine, infrav1.InstanceReadyCondition, infrav1.InstancePendingReason, clusterv1.ConditionSeverityInfo,
+			"instance %s is in %s state", *instance.ID, state)
+	}
+}
+
+// GetAddresses returns the node addresses of the instance.
+func (m *MachineScope) GetAddresses(instance *vpcv1.Instance) []corev1.NodeAddress {
+	var addresses []corev1.NodeAddress
+	if instance.PrimaryNetworkInterface != nil && instance.PrimaryNetworkInterface.PrimaryIpv4Address != nil && *instance.PrimaryNetworkInterface.PrimaryIpv4Address != "" {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalIP,
+			Address: *instance.PrimaryNetworkInterface.PrimaryIpv4Address,
+		})
+	}
+	return addresses
+}
+
 // PatchObject persists the cluster configuration and status.
 func (m *MachineScope) PatchObject() error {
 	return m.patchHelper.Patch(context.TODO(), m.IBMVPCMachine)
@@ -206,5 +697,74 @@ func (m *MachineScope) GetBootstrapData() (string, error) {
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
-	return string(value), nil
+
+	format := string(secret.Data["format"])
+
+	if len(m.IBMVPCMachine.Spec.AdditionalUserData) > 0 {
+		if format == bootstrapFormatIgnition {
+			return "", errors.New("additional user data can not be merged with Ignition bootstrap data")
+		}
+		refs := make([]v1alpha4.UserDataSecretReference, len(m.IBMVPCMachine.Spec.AdditionalUserData))
+		for i := range m.IBMVPCMachine.Spec.AdditionalUserData {
+			if err := infrav1.Convert_v1alpha3_UserDataSecretReference_To_v1alpha4_UserDataSecretReference(&m.IBMVPCMachine.Spec.AdditionalUserData[i], &refs[i], nil); err != nil {
+				return "", err
+			}
+		}
+		parts, err := getAdditionalUserData(m.client, m.IBMVPCMachine.Namespace, refs)
+		if err != nil {
+			return "", err
+		}
+		if value, err = mergeUserData(value, parts); err != nil {
+			return "", err
+		}
+	}
+
+	var dataURL string
+	if store, err := m.bootstrapDataStore(); err != nil {
+		return "", err
+	} else if store != nil {
+		objectKey := bootstrapDataObjectKey(m.IBMVPCMachine.Namespace, m.IBMVPCMachine.Name)
+		if dataURL, err = store.Put(objectKey, value); err != nil {
+			return "", err
+		}
+		m.IBMVPCMachine.Status.BootstrapDataObject = objectKey
+	}
+
+	userData, err := bootstrapUserData(format, value, dataURL, ignitionOptions{
+		Hostname:      m.IBMVPCMachine.Name,
+		FetchMetadata: m.trustedProfileID() != "" || m.IBMVPCMachine.Spec.MetadataService,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate user data for IBMVPCMachine %s/%s", m.IBMVPCMachine.Namespace, m.IBMVPCMachine.Name)
+	}
+	return string(userData), nil
+}
+
+// bootstrapDataStore returns the store of the bootstrap data, or nil if the cluster delivers it in the user data.
+func (m *MachineScope) bootstrapDataStore() (*bootstrapDataStore, error) {
+	if m.IBMVPCCluster == nil || m.IBMVPCCluster.Spec.BootstrapStorage == nil {
+		return nil, nil
+	}
+	storage := &v1alpha4.BootstrapStorage{}
+	if err := infrav1.Convert_v1alpha3_BootstrapStorage_To_v1alpha4_BootstrapStorage(m.IBMVPCCluster.Spec.BootstrapStorage, storage, nil); err != nil {
+		return nil, err
+	}
+	return newBootstrapDataStore(storage, m.Cluster.Name)
+}
+
+// DeleteBootstrapData deletes the bootstrap data uploaded to Cloud Object Storage for the instance.
+func (m *MachineScope) DeleteBootstrapData() error {
+	if m.IBMVPCMachine.Status.BootstrapDataObject == "" {
+		return nil
+	}
+	store, err := m.bootstrapDataStore()
+	if err != nil {
+		return err
+	} else if store != nil {
+		if err := store.Delete(m.IBMVPCMachine.Status.BootstrapDataObject); err != nil {
+			return errors.Wrap(err, "failed to delete bootstrap data")
+		}
+	}
+	m.IBMVPCMachine.Status.BootstrapDataObject = ""
+	return nil
 }
@@ -0,0 +1,596 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/exp/api/v1alpha4"
+)
+
+// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
+type MachinePoolScopeParams struct {
+	IBMVPCClients
+	Client            client.Client
+	Logger            logr.Logger
+	Cluster           *clusterv1.Cluster
+	MachinePool       *expclusterv1.MachinePool
+	IBMVPCCluster     *infrav1.IBMVPCCluster
+	IBMVPCMachinePool *expinfrav1.IBMVPCMachinePool
+}
+
+// MachinePoolScope defines a scope defined around a machine pool and its cluster.
+type MachinePoolScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+
+	IBMVPCClients
+	Cluster     *clusterv1.Cluster
+	MachinePool *expclusterv1.MachinePool
+
+	IBMVPCCluster     *infrav1.IBMVPCCluster
+	IBMVPCMachinePool *expinfrav1.IBMVPCMachinePool
+}
+
+// NewMachinePoolScope creates a new MachinePoolScope from the supplied parameters.
+func NewMachinePoolScope(params MachinePoolScopeParams, authenticator core.Authenticator, svcEndpoint string) (*MachinePoolScope, error) {
+	if params.MachinePool == nil {
+		return nil, errors.New("failed to generate new scope from nil MachinePool")
+	}
+	if params.IBMVPCMachinePool == nil {
+		return nil, errors.New("failed to generate new scope from nil IBMVPCMachinePool")
+	}
+	if params.IBMVPCCluster == nil {
+		return nil, errors.New("failed to generate new scope from nil IBMVPCCluster")
+	}
+
+	if params.Logger == nil {
+		params.Logger = klogr.New()
+	}
+
+	helper, err := patch.NewHelper(params.IBMVPCMachinePool, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	vpcErr := params.IBMVPCClients.setIBMVPCService(authenticator, svcEndpoint)
+	if vpcErr != nil {
+		return nil, errors.Wrap(vpcErr, "failed to create IBM VPC session")
+	}
+
+	if err := params.IBMVPCClients.setGlobalTaggingService(authenticator); err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud Global Tagging session")
+	}
+
+	return &MachinePoolScope{
+		Logger:            params.Logger,
+		client:            params.Client,
+		IBMVPCClients:     params.IBMVPCClients,
+		Cluster:           params.Cluster,
+		MachinePool:       params.MachinePool,
+		IBMVPCCluster:     params.IBMVPCCluster,
+		IBMVPCMachinePool: params.IBMVPCMachinePool,
+		patchHelper:       helper,
+	}, nil
+}
+
+// ReconcileInstanceTemplate makes sure the instance template matching the current pool spec exists. The template it
+// replaces is recorded in the status to be deleted once the instance group no longer uses it.
+func (m *MachinePoolScope) ReconcileInstanceTemplate() error {
+	bootstrapData, err := m.GetBootstrapData()
+	if err != nil {
+		return err
+	}
+	templateName, err := m.instanceTemplateName(bootstrapData)
+	if err != nil {
+		return err
+	}
+	status := &m.IBMVPCMachinePool.Status
+	if status.InstanceTemplateName == templateName && status.InstanceTemplateID != "" {
+		return nil
+	}
+
+	template, err := m.ensureInstanceTemplateUnique(templateName)
+	if err != nil {
+		return err
+	}
+	if template == nil {
+		if template, err = m.createInstanceTemplate(templateName, bootstrapData); err != nil {
+			return errors.Wrapf(err, "failed to create instance template %s", templateName)
+		}
+	}
+
+	if status.InstanceTemplateID != "" && status.InstanceTemplateID != *template.ID {
+		status.PreviousInstanceTemplateIDs = append(status.PreviousInstanceTemplateIDs, status.InstanceTemplateID)
+	}
+	status.InstanceTemplateID = *template.ID
+	status.InstanceTemplateName = templateName
+	return nil
+}
+
+// instanceTemplateName returns the name of the instance template, suffixed by a hash of the fields of the pool spec
+// defining the instances and of the bootstrap data captured in the template, so that a new bootstrap data secret or
+// a change of its value rolls out a new template.
+func (m *MachinePoolScope) instanceTemplateName(bootstrapData string) (string, error) {
+	spec := m.IBMVPCMachinePool.Spec
+	bootstrapDataHash := sha256.Sum256([]byte(bootstrapData))
+	specData, err := json.Marshal(struct {
+		Image               infrav1.IBMVPCResourceReference    `json:"image"`
+		Zone                string                             `json:"zone"`
+		Profile             string                             `json:"profile"`
+		SSHKeys             []*infrav1.IBMVPCResourceReference `json:"sshKeys,omitempty"`
+		Subnet              *infrav1.IBMVPCResourceReference   `json:"subnet,omitempty"`
+		BootstrapDataSecret string                             `json:"bootstrapDataSecret"`
+		BootstrapDataHash   string                             `json:"bootstrapDataHash"`
+	}{
+		Image:               infrav1.IBMVPCResourceReference(spec.Image),
+		Zone:                spec.Zone,
+		Profile:             spec.Profile,
+		SSHKeys:             m.sshKeyReferences(),
+		Subnet:              (*infrav1.IBMVPCResourceReference)(spec.Subnet),
+		BootstrapDataSecret: *m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName,
+		BootstrapDataHash:   hex.EncodeToString(bootstrapDataHash[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(specData)
+
+	// VPC resource names are limited to 63 characters.
+	name := m.IBMVPCMachinePool.Name
+	if len(name) > 54 {
+		name = name[:54]
+	}
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(hash[:])[:8]), nil
+}
+
+func (m *MachinePoolScope) ensureInstanceTemplateUnique(templateName string) (*vpcv1.InstanceTemplate, error) {
+	templates, err := m.listInstanceTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if template, ok := t.(*vpcv1.InstanceTemplate); ok && *template.Name == templateName {
+			return template, nil
+		}
+	}
+	return nil, nil
+}
+
+// listInstanceTemplates returns the instance templates of all the pages. ListInstanceTemplatesOptions does not take
+// the start of a page in this version of the SDK, the next pages are requested with the start of their link.
+func (m *MachinePoolScope) listInstanceTemplates() ([]vpcv1.InstanceTemplateIntf, error) {
+	collection, _, err := m.IBMVPCClients.VPCService.ListInstanceTemplates(&vpcv1.ListInstanceTemplatesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	templates := collection.Templates
+	for collection.Next != nil && collection.Next.Href != nil {
+		next, err := url.Parse(*collection.Next.Href)
+		if err != nil {
+			return nil, err
+		}
+		start := next.Query().Get("start")
+		if start == "" {
+			break
+		}
+
+		builder := core.NewRequestBuilder(core.GET)
+		if _, err := builder.ResolveRequestURL(m.IBMVPCClients.VPCService.Service.GetServiceURL(), "/instance/templates", nil); err != nil {
+			return nil, err
+		}
+		builder.AddHeader("Accept", "application/json")
+		builder.AddQuery("version", *m.IBMVPCClients.VPCService.Version)
+		builder.AddQuery("generation", "2")
+		builder.AddQuery("start", start)
+		request, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+		var rawResponse map[string]json.RawMessage
+		if _, err := m.IBMVPCClients.VPCService.Service.Request(request, &rawResponse); err != nil {
+			return nil, err
+		}
+		collection = nil
+		if err := core.UnmarshalModel(rawResponse, "", &collection, vpcv1.UnmarshalInstanceTemplateCollection); err != nil {
+			return nil, err
+		}
+		templates = append(templates, collection.Templates...)
+	}
+	return templates, nil
+}
+
+func (m *MachinePoolScope) createInstanceTemplate(templateName, bootstrapData string) (*vpcv1.InstanceTemplate, error) {
+	spec := m.IBMVPCMachinePool.Spec
+	imageID, err := m.getImageID(infrav1.IBMVPCResourceReference(spec.Image))
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting image ID")
+	}
+	subnetID, err := m.getSubnetID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting subnet ID")
+	}
+	sshKeyIDs, err := m.getSSHKeyIDs(m.sshKeyReferences())
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting SSH key IDs")
+	}
+
+	prototype := &vpcv1.InstanceTemplatePrototypeInstanceByImage{
+		Name: &templateName,
+		Image: &vpcv1.ImageIdentity{
+			ID: imageID,
+		},
+		Profile: &vpcv1.InstanceProfileIdentity{
+			Name: &spec.Profile,
+		},
+		Zone: &vpcv1.ZoneIdentity{
+			Name: &spec.Zone,
+		},
+		PrimaryNetworkInterface: &vpcv1.NetworkInterfacePrototype{
+			Subnet: &vpcv1.SubnetIdentity{
+				ID: subnetID,
+			},
+		},
+		ResourceGroup: &vpcv1.ResourceGroupIdentity{
+			ID: &m.IBMVPCCluster.Status.ResourceGroupID,
+		},
+		UserData: &bootstrapData,
+	}
+	for i := range sshKeyIDs {
+		prototype.Keys = append(prototype.Keys, &vpcv1.KeyIdentity{
+			ID: &sshKeyIDs[i],
+		})
+	}
+
+	options := &vpcv1.CreateInstanceTemplateOptions{}
+	options.SetInstanceTemplatePrototype(prototype)
+	result, _, err := m.IBMVPCClients.VPCService.CreateInstanceTemplate(options)
+	if err != nil {
+		return nil, err
+	}
+	template, ok := result.(*vpcv1.InstanceTemplate)
+	if !ok {
+		return nil, fmt.Errorf("unexpected instance template type %T", result)
+	}
+	return template, nil
+}
+
+// sshKeyReferences returns the references to the SSH keys of the pool.
+func (m *MachinePoolScope) sshKeyReferences() []*infrav1.IBMVPCResourceReference {
+	var sshKeys []*infrav1.IBMVPCResourceReference
+	for _, sshKey := range m.IBMVPCMachinePool.Spec.SSHKeys {
+		if sshKey != nil {
+			sshKeys = append(sshKeys, (*infrav1.IBMVPCResourceReference)(sshKey))
+		}
+	}
+	return sshKeys
+}
+
+// getSubnetID resolves the subnet of the pool, defaulting to the subnet of the cluster.
+func (m *MachinePoolScope) getSubnetID() (*string, error) {
+	subnet := m.IBMVPCMachinePool.Spec.Subnet
+	if subnet == nil {
+		if m.IBMVPCCluster.Status.Subnet.ID == nil {
+			return nil, fmt.Errorf("subnet of the cluster is not yet available")
+		}
+		return m.IBMVPCCluster.Status.Subnet.ID, nil
+	}
+	return m.IBMVPCClients.getSubnetID((*infrav1.IBMVPCResourceReference)(subnet), m.IBMVPCCluster.Status.VPC.ID)
+}
+
+// DeletePreviousInstanceTemplates deletes the instance templates replaced by the current one which are neither
+// the template of the instance group nor the template of one of its instances anymore.
+func (m *MachinePoolScope) DeletePreviousInstanceTemplates(instanceGroup *vpcv1.InstanceGroup, memberships []vpcv1.InstanceGroupMembership) error {
+	used := map[string]bool{}
+	if instanceGroup != nil && instanceGroup.InstanceTemplate != nil && instanceGroup.InstanceTemplate.ID != nil {
+		used[*instanceGroup.InstanceTemplate.ID] = true
+	}
+	for _, membership := range memberships {
+		if membership.InstanceTemplate != nil && membership.InstanceTemplate.ID != nil {
+			used[*membership.InstanceTemplate.ID] = true
+		}
+	}
+
+	status := &m.IBMVPCMachinePool.Status
+	var remaining []string
+	for i, templateID := range status.PreviousInstanceTemplateIDs {
+		if templateID == status.InstanceTemplateID {
+			continue
+		}
+		if used[templateID] {
+			remaining = append(remaining, templateID)
+			continue
+		}
+		if err := m.DeleteInstanceTemplate(templateID); err != nil {
+			status.PreviousInstanceTemplateIDs = append(remaining, status.PreviousInstanceTemplateIDs[i:]...)
+			return errors.Wrapf(err, "failed to delete instance template %s", templateID)
+		}
+		m.Info("deleted previous instance template", "id", templateID)
+	}
+	status.PreviousInstanceTemplateIDs = remaining
+	return nil
+}
+
+// DeleteInstanceTemplate deletes an instance template, it is not found once deleted.
+func (m *MachinePoolScope) DeleteInstanceTemplate(templateID string) error {
+	options := &vpcv1.DeleteInstanceTemplateOptions{}
+	options.SetID(templateID)
+	response, err := m.IBMVPCClients.VPCService.DeleteInstanceTemplate(options)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	return nil
+}
+
+// ReconcileInstanceGroup makes sure the instance group exists, uses the current instance template and
+// is sized to the replicas of the machine pool.
+func (m *MachinePoolScope) ReconcileInstanceGroup() (*vpcv1.InstanceGroup, error) {
+	status := &m.IBMVPCMachinePool.Status
+	replicas := int64(1)
+	if m.MachinePool.Spec.Replicas != nil {
+		replicas = int64(*m.MachinePool.Spec.Replicas)
+	}
+
+	var instanceGroup *vpcv1.InstanceGroup
+	if status.InstanceGroupID != "" {
+		options := &vpcv1.GetInstanceGroupOptions{}
+		options.SetID(status.InstanceGroupID)
+		group, response, err := m.IBMVPCClients.VPCService.GetInstanceGroup(options)
+		if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+			return nil, err
+		}
+		instanceGroup = group
+	}
+	if instanceGroup == nil {
+		group, err := m.ensureInstanceGroupUnique(m.IBMVPCMachinePool.Name)
+		if err != nil {
+			return nil, err
+		}
+		instanceGroup = group
+	}
+	if instanceGroup == nil {
+		group, err := m.createInstanceGroup(replicas)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create instance group %s", m.IBMVPCMachinePool.Name)
+		}
+		status.InstanceGroupID = *group.ID
+		return group, nil
+	}
+	status.InstanceGroupID = *instanceGroup.ID
+
+	patch := &vpcv1.InstanceGroupPatch{}
+	changed := false
+	if *instanceGroup.InstanceTemplate.ID != status.InstanceTemplateID {
+		patch.InstanceTemplate = &vpcv1.InstanceTemplateIdentity{
+			ID: &status.InstanceTemplateID,
+		}
+		changed = true
+	}
+	if *instanceGroup.MembershipCount != replicas {
+		patch.MembershipCount = &replicas
+		changed = true
+	}
+	if !changed {
+		return instanceGroup, nil
+	}
+	patchMap, err := patch.AsPatch()
+	if err != nil {
+		return nil, err
+	}
+	options := &vpcv1.UpdateInstanceGroupOptions{}
+	options.SetID(*instanceGroup.ID)
+	options.SetInstanceGroupPatch(patchMap)
+	instanceGroup, _, err = m.IBMVPCClients.VPCService.UpdateInstanceGroup(options)
+	return instanceGroup, err
+}
+
+func (m *MachinePoolScope) ensureInstanceGroupUnique(instanceGroupName string) (*vpcv1.InstanceGroup, error) {
+	options := &vpcv1.ListInstanceGroupsOptions{}
+	for {
+		instanceGroups, _, err := m.IBMVPCClients.VPCService.ListInstanceGroups(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, instanceGroup := range instanceGroups.InstanceGroups {
+			if *instanceGroup.Name == instanceGroupName {
+				return &instanceGroup, nil
+			}
+		}
+		start, err := instanceGroups.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			return nil, nil
+		}
+		options.SetStart(*start)
+	}
+}
+
+func (m *MachinePoolScope) createInstanceGroup(replicas int64) (*vpcv1.InstanceGroup, error) {
+	subnetID, err := m.getSubnetID()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting subnet ID")
+	}
+
+	options := &vpcv1.CreateInstanceGroupOptions{}
+	options.SetName(m.IBMVPCMachinePool.Name)
+	options.SetInstanceTemplate(&vpcv1.InstanceTemplateIdentity{
+		ID: &m.IBMVPCMachinePool.Status.InstanceTemplateID,
+	})
+	options.SetSubnets([]vpcv1.SubnetIdentityIntf{
+		&vpcv1.SubnetIdentity{
+			ID: subnetID,
+		},
+	})
+	options.SetMembershipCount(replicas)
+	options.SetResourceGroup(&vpcv1.ResourceGroupIdentity{
+		ID: &m.IBMVPCCluster.Status.ResourceGroupID,
+	})
+	instanceGroup, _, err := m.IBMVPCClients.VPCService.CreateInstanceGroup(options)
+	return instanceGroup, err
+}
+
+// GetMemberships returns the memberships of the instance group.
+func (m *MachinePoolScope) GetMemberships() ([]vpcv1.InstanceGroupMembership, error) {
+	var memberships []vpcv1.InstanceGroupMembership
+	options := &vpcv1.ListInstanceGroupMembershipsOptions{}
+	options.SetInstanceGroupID(m.IBMVPCMachinePool.Status.InstanceGroupID)
+	for {
+		collection, _, err := m.IBMVPCClients.VPCService.ListInstanceGroupMemberships(options)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, collection.Memberships...)
+		start, err := collection.GetNextStart()
+		if err != nil {
+			return nil, err
+		} else if start == nil {
+			return memberships, nil
+		}
+		options.SetStart(*start)
+	}
+}
+
+// RollOutInstanceTemplate replaces one instance created from a previous instance template at a time, once all the
+// instances of the group are healthy. It returns whether instances from previous templates remain.
+func (m *MachinePoolScope) RollOutInstanceTemplate(memberships []vpcv1.InstanceGroupMembership) (bool, error) {
+	var outdated []vpcv1.InstanceGroupMembership
+	for _, membership := range memberships {
+		if *membership.Status != vpcv1.InstanceGroupMembershipStatusHealthyConst {
+			// Wait for the group to settle before replacing more instances.
+			return true, nil
+		}
+		if membership.InstanceTemplate == nil || *membership.InstanceTemplate.ID != m.IBMVPCMachinePool.Status.InstanceTemplateID {
+			outdated = append(outdated, membership)
+		}
+	}
+	if len(outdated) == 0 {
+		return false, nil
+	}
+
+	membership := outdated[0]
+	m.Info("replacing instance created from a previous instance template", "instance", *membership.Instance.Name)
+	options := &vpcv1.DeleteInstanceGroupMembershipOptions{}
+	options.SetInstanceGroupID(m.IBMVPCMachinePool.Status.InstanceGroupID)
+	options.SetID(*membership.ID)
+	response, err := m.IBMVPCClients.VPCService.DeleteInstanceGroupMembership(options)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return true, err
+	}
+	return true, nil
+}
+
+// ProviderID returns the provider ID of an instance of the pool.
+func (m *MachinePoolScope) ProviderID(instance *vpcv1.InstanceReference) string {
+	return fmt.Sprintf("ibmvpc://%s/%s", m.MachinePool.Spec.ClusterName, *instance.Name)
+}
+
+// ReconcileTags attaches the cluster and pool tags to the instances of the group, re-attaching the ones
+// detached since the last reconciliation. Tags removed from the spec are not detached.
+func (m *MachinePoolScope) ReconcileTags(memberships []vpcv1.InstanceGroupMembership) error {
+	tags := clusterTags(m.Cluster, TagRoleNode, m.IBMVPCCluster.Spec.Tags, m.IBMVPCMachinePool.Spec.Tags)
+	accessTags := append(append([]string{}, m.IBMVPCCluster.Spec.AccessTags...), m.IBMVPCMachinePool.Spec.AccessTags...)
+	for _, membership := range memberships {
+		if membership.Instance == nil || membership.Instance.CRN == nil {
+			continue
+		}
+		if err := reconcileTags(m.IBMVPCClients.TaggingClient, *membership.Instance.CRN, tags, accessTags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteInstanceGroup deletes the instance group and its instances, it returns true once the group is gone.
+func (m *MachinePoolScope) DeleteInstanceGroup() (bool, error) {
+	instanceGroupID := m.IBMVPCMachinePool.Status.InstanceGroupID
+	if instanceGroupID == "" {
+		return true, nil
+	}
+
+	getOptions := &vpcv1.GetInstanceGroupOptions{}
+	getOptions.SetID(instanceGroupID)
+	instanceGroup, response, err := m.IBMVPCClients.VPCService.GetInstanceGroup(getOptions)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	if *instanceGroup.Status == vpcv1.InstanceGroupStatusDeletingConst {
+		return false, nil
+	}
+
+	deleteOptions := &vpcv1.DeleteInstanceGroupOptions{}
+	deleteOptions.SetID(instanceGroupID)
+	response, err = m.IBMVPCClients.VPCService.DeleteInstanceGroup(deleteOptions)
+	if err != nil && (response == nil || response.StatusCode != http.StatusNotFound) {
+		return false, err
+	}
+	return false, nil
+}
+
+// GetBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName
+func (m *MachinePoolScope) GetBootstrapData() (string, error) {
+	if m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		return "", errors.New("error retrieving bootstrap data: linked MachinePool's bootstrap.dataSecretName is nil")
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.MachinePool.Namespace, Name: *m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
+	if err := m.client.Get(context.TODO(), key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for IBMVPCMachinePool %s/%s", m.MachinePool.Namespace, m.MachinePool.Name)
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+	return string(value), nil
+}
+
+// PatchObject persists the machine pool configuration and status.
+func (m *MachinePoolScope) PatchObject() error {
+	return m.patchHelper.Patch(context.TODO(), m.IBMVPCMachinePool)
+}
+
+// Close closes the current scope persisting the machine pool configuration and status.
+func (m *MachinePoolScope) Close() error {
+	return m.PatchObject()
+}
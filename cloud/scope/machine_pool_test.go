@@ -0,0 +1,334 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/exp/api/v1alpha4"
+)
+
+// fakeInstanceTemplates is an in-memory stand-in of the instance templates of the VPC API, listed two per page.
+type fakeInstanceTemplates struct {
+	mu        sync.Mutex
+	serverURL string
+	templates []map[string]string
+	created   []string
+	deleted   []string
+}
+
+func (f *fakeInstanceTemplates) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/instance/templates":
+		start := 0
+		if s := r.URL.Query().Get("start"); s != "" {
+			fmt.Sscanf(s, "%d", &start)
+		}
+		end := start + 2
+		if end > len(f.templates) {
+			end = len(f.templates)
+		}
+		page := map[string]interface{}{
+			"first":       map[string]string{"href": f.serverURL + "/instance/templates"},
+			"limit":       2,
+			"templates":   f.templates[start:end],
+			"total_count": len(f.templates),
+		}
+		if end < len(f.templates) {
+			page["next"] = map[string]string{"href": fmt.Sprintf("%s/instance/templates?start=%d", f.serverURL, end)}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	case r.Method == http.MethodPost && r.URL.Path == "/instance/templates":
+		body := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		template := map[string]string{"id": fmt.Sprintf("template-%d", len(f.templates)), "name": body["name"].(string)}
+		f.templates = append(f.templates, template)
+		f.created = append(f.created, template["name"])
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(template)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/instance/templates/"):
+		id := strings.TrimPrefix(r.URL.Path, "/instance/templates/")
+		for i, template := range f.templates {
+			if template["id"] == id {
+				f.templates = append(f.templates[:i], f.templates[i+1:]...)
+				f.deleted = append(f.deleted, id)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"not_found","message":"not found"}]}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newMachinePoolScope(t *testing.T, handler http.Handler) *MachinePoolScope {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	if templates, ok := handler.(*fakeInstanceTemplates); ok {
+		templates.serverURL = server.URL
+	}
+
+	vpcService, err := vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
+		URL:           server.URL,
+		Authenticator: &core.NoAuthAuthenticator{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool-bootstrap"},
+		Data:       map[string][]byte{"value": []byte("#cloud-config\n")},
+	}
+	nextSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool-bootstrap-next"},
+		Data:       map[string][]byte{"value": []byte("#cloud-config\n")},
+	}
+
+	return &MachinePoolScope{
+		Logger:        klogr.New(),
+		client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, nextSecret).Build(),
+		IBMVPCClients: IBMVPCClients{VPCService: vpcService},
+		Cluster:       &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "capi"}},
+		MachinePool: &expclusterv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool"},
+			Spec: expclusterv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: core.StringPtr("pool-bootstrap")},
+				}},
+			},
+		},
+		IBMVPCCluster: &infrav1.IBMVPCCluster{Status: infrav1.IBMVPCClusterStatus{
+			ResourceGroupID: "resource-group",
+			Subnet:          infrav1.Subnet{ID: core.StringPtr("subnet")},
+		}},
+		IBMVPCMachinePool: &expinfrav1.IBMVPCMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool"},
+			Spec: expinfrav1.IBMVPCMachinePoolSpec{
+				Image:   infrav1alpha4.IBMVPCResourceReference{ID: core.StringPtr("image")},
+				Zone:    "us-south-1",
+				Profile: "bx2-4x16",
+			},
+		},
+	}
+}
+
+func instanceTemplateName(g *WithT, m *MachinePoolScope) string {
+	bootstrapData, err := m.GetBootstrapData()
+	g.Expect(err).NotTo(HaveOccurred())
+	name, err := m.instanceTemplateName(bootstrapData)
+	g.Expect(err).NotTo(HaveOccurred())
+	return name
+}
+
+// rotateBootstrapData changes the value of the bootstrap data secret of the pool, as a renewed join token does.
+func rotateBootstrapData(t *testing.T, m *MachinePoolScope) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: "default", Name: *m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
+	if err := m.client.Get(context.TODO(), key, secret); err != nil {
+		t.Fatal(err)
+	}
+	secret.Data["value"] = []byte("#cloud-config\nruncmd: [kubeadm join --token rotated]\n")
+	if err := m.client.Update(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstanceTemplateName(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *MachinePoolScope)
+		same   bool
+	}{
+		{
+			name: "provider ids do not change the template",
+			modify: func(m *MachinePoolScope) {
+				m.IBMVPCMachinePool.Spec.ProviderIDList = []string{"ibmvpc://capi/pool-1"}
+			},
+			same: true,
+		},
+		{
+			name: "tags do not change the template",
+			modify: func(m *MachinePoolScope) {
+				m.IBMVPCMachinePool.Spec.Tags = []string{"team:a"}
+				m.IBMVPCMachinePool.Spec.AccessTags = []string{"project:a"}
+			},
+			same: true,
+		},
+		{
+			name: "bootstrap data secret changes the template",
+			modify: func(m *MachinePoolScope) {
+				m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName = core.StringPtr("pool-bootstrap-next")
+			},
+		},
+		{
+			name: "bootstrap data changes the template",
+			modify: func(m *MachinePoolScope) {
+				rotateBootstrapData(t, m)
+			},
+		},
+		{
+			name: "profile changes the template",
+			modify: func(m *MachinePoolScope) {
+				m.IBMVPCMachinePool.Spec.Profile = "bx2-8x32"
+			},
+		},
+		{
+			name: "image changes the template",
+			modify: func(m *MachinePoolScope) {
+				m.IBMVPCMachinePool.Spec.Image = infrav1alpha4.IBMVPCResourceReference{Name: core.StringPtr("ubuntu")}
+			},
+		},
+		{
+			name: "SSH keys change the template",
+			modify: func(m *MachinePoolScope) {
+				m.IBMVPCMachinePool.Spec.SSHKeys = []*infrav1alpha4.IBMVPCResourceReference{{ID: core.StringPtr("key")}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newMachinePoolScope(t, http.NotFoundHandler())
+			name := instanceTemplateName(g, m)
+			g.Expect(name).To(HavePrefix("pool-"))
+
+			tt.modify(m)
+			modified := instanceTemplateName(g, m)
+			if tt.same {
+				g.Expect(modified).To(Equal(name))
+			} else {
+				g.Expect(modified).NotTo(Equal(name))
+			}
+		})
+	}
+}
+
+func TestReconcileInstanceTemplate(t *testing.T) {
+	g := NewWithT(t)
+	templates := &fakeInstanceTemplates{}
+	m := newMachinePoolScope(t, templates)
+	name := instanceTemplateName(g, m)
+
+	// The template of the pool is found on the last page.
+	templates.templates = []map[string]string{
+		{"id": "t-0", "name": "other-0"},
+		{"id": "t-1", "name": "other-1"},
+		{"id": "t-2", "name": "other-2"},
+		{"id": "t-3", "name": name},
+	}
+	g.Expect(m.ReconcileInstanceTemplate()).To(Succeed())
+	g.Expect(templates.created).To(BeEmpty())
+	g.Expect(m.IBMVPCMachinePool.Status.InstanceTemplateID).To(Equal("t-3"))
+	g.Expect(m.IBMVPCMachinePool.Status.PreviousInstanceTemplateIDs).To(BeEmpty())
+
+	// A spec change creates a new template and records the replaced one.
+	m.IBMVPCMachinePool.Spec.Profile = "bx2-8x32"
+	g.Expect(m.ReconcileInstanceTemplate()).To(Succeed())
+	g.Expect(templates.created).To(HaveLen(1))
+	g.Expect(m.IBMVPCMachinePool.Status.InstanceTemplateID).To(Equal("template-4"))
+	g.Expect(m.IBMVPCMachinePool.Status.PreviousInstanceTemplateIDs).To(Equal([]string{"t-3"}))
+	g.Expect(templates.deleted).To(BeEmpty())
+
+	// Reconciling the same spec again is a no-op.
+	g.Expect(m.ReconcileInstanceTemplate()).To(Succeed())
+	g.Expect(templates.created).To(HaveLen(1))
+
+	// A rotated bootstrap data secret creates a new template.
+	rotateBootstrapData(t, m)
+	g.Expect(m.ReconcileInstanceTemplate()).To(Succeed())
+	g.Expect(templates.created).To(HaveLen(2))
+	g.Expect(m.IBMVPCMachinePool.Status.InstanceTemplateID).To(Equal("template-5"))
+	g.Expect(m.IBMVPCMachinePool.Status.PreviousInstanceTemplateIDs).To(Equal([]string{"t-3", "template-4"}))
+}
+
+func TestDeletePreviousInstanceTemplates(t *testing.T) {
+	membership := func(templateID string) vpcv1.InstanceGroupMembership {
+		return vpcv1.InstanceGroupMembership{InstanceTemplate: &vpcv1.InstanceTemplateReference{ID: core.StringPtr(templateID)}}
+	}
+
+	tests := []struct {
+		name          string
+		groupTemplate string
+		memberships   []vpcv1.InstanceGroupMembership
+		wantDeleted   []string
+		wantRemaining []string
+	}{
+		{
+			name:          "keeps the template of the instance group",
+			groupTemplate: "t-0",
+			memberships:   []vpcv1.InstanceGroupMembership{membership("t-1")},
+			wantRemaining: []string{"t-0"},
+		},
+		{
+			name:          "keeps the template of an instance",
+			groupTemplate: "t-1",
+			memberships:   []vpcv1.InstanceGroupMembership{membership("t-0"), membership("t-1")},
+			wantRemaining: []string{"t-0"},
+		},
+		{
+			name:          "deletes the template once it is no longer used",
+			groupTemplate: "t-1",
+			memberships:   []vpcv1.InstanceGroupMembership{membership("t-1")},
+			wantDeleted:   []string{"t-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			templates := &fakeInstanceTemplates{templates: []map[string]string{{"id": "t-0"}, {"id": "t-1"}}}
+			m := newMachinePoolScope(t, templates)
+			m.IBMVPCMachinePool.Status.InstanceTemplateID = "t-1"
+			m.IBMVPCMachinePool.Status.PreviousInstanceTemplateIDs = []string{"t-0"}
+			group := &vpcv1.InstanceGroup{InstanceTemplate: &vpcv1.InstanceTemplateReference{ID: core.StringPtr(tt.groupTemplate)}}
+
+			g.Expect(m.DeletePreviousInstanceTemplates(group, tt.memberships)).To(Succeed())
+			g.Expect(templates.deleted).To(Equal(tt.wantDeleted))
+			g.Expect(m.IBMVPCMachinePool.Status.PreviousInstanceTemplateIDs).To(Equal(tt.wantRemaining))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+)
+
+// MachineTemplateScopeParams defines the input parameters used to create a new MachineTemplateScope.
+type MachineTemplateScopeParams struct {
+	IBMVPCClients
+	Client                client.Client
+	Logger                logr.Logger
+	IBMVPCMachineTemplate *infrav1.IBMVPCMachineTemplate
+}
+
+// MachineTemplateScope defines a scope defined around a machine template.
+type MachineTemplateScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+
+	IBMVPCClients
+	IBMVPCMachineTemplate *infrav1.IBMVPCMachineTemplate
+}
+
+// NewMachineTemplateScope creates a new MachineTemplateScope from the supplied parameters.
+func NewMachineTemplateScope(params MachineTemplateScopeParams, authenticator core.Authenticator, svcEndpoint string) (*MachineTemplateScope, error) {
+	if params.IBMVPCMachineTemplate == nil {
+		return nil, errors.New("failed to generate new scope from nil IBMVPCMachineTemplate")
+	}
+
+	if params.Logger == nil {
+		params.Logger = klogr.New()
+	}
+
+	helper, err := patch.NewHelper(params.IBMVPCMachineTemplate, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	vpcErr := params.IBMVPCClients.setIBMVPCService(authenticator, svcEndpoint)
+	if vpcErr != nil {
+		return nil, errors.Wrap(vpcErr, "failed to create IBM VPC session")
+	}
+
+	return &MachineTemplateScope{
+		Logger:                params.Logger,
+		client:                params.Client,
+		IBMVPCClients:         params.IBMVPCClients,
+		IBMVPCMachineTemplate: params.IBMVPCMachineTemplate,
+		patchHelper:           helper,
+	}, nil
+}
+
+// ReconcileCapacity validates the instance profile of the template and publishes its capacity in the status.
+func (m *MachineTemplateScope) ReconcileCapacity() error {
+	profileName := m.IBMVPCMachineTemplate.Spec.Template.Spec.Profile
+	profile, err := m.getInstanceProfile(profileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get instance profile %s", profileName)
+	}
+	if profile == nil {
+		m.IBMVPCMachineTemplate.Status.Capacity = nil
+		conditions.MarkFalse(m.IBMVPCMachineTemplate, infrav1.InstanceProfileValidCondition, infrav1.InstanceProfileNotFoundReason, clusterv1.ConditionSeverityError,
+			"instance profile %s not found", profileName)
+		return nil
+	}
+
+	m.IBMVPCMachineTemplate.Status.Capacity = instanceProfileCapacity(profile)
+	conditions.MarkTrue(m.IBMVPCMachineTemplate, infrav1.InstanceProfileValidCondition)
+	return nil
+}
+
+// instanceProfileCapacity returns the vCPU, memory and GPU capacity of an instance profile,
+// profiles with a configurable value report their default.
+func instanceProfileCapacity(profile *vpcv1.InstanceProfile) corev1.ResourceList {
+	capacity := corev1.ResourceList{}
+	if vcpu, ok := profile.VcpuCount.(*vpcv1.InstanceProfileVcpu); ok {
+		if count := profileValue(vcpu.Value, vcpu.Default); count > 0 {
+			capacity[corev1.ResourceCPU] = *resource.NewQuantity(count, resource.DecimalSI)
+		}
+	}
+	if memory, ok := profile.Memory.(*vpcv1.InstanceProfileMemory); ok {
+		if size := profileValue(memory.Value, memory.Default); size > 0 {
+			capacity[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%dGi", size))
+		}
+	}
+	if gpu, ok := profile.GpuCount.(*vpcv1.InstanceProfileGpu); ok {
+		if count := profileValue(gpu.Value, gpu.Default); count > 0 {
+			manufacturer := "nvidia"
+			if profile.GpuManufacturer != nil && len(profile.GpuManufacturer.Values) > 0 {
+				manufacturer = strings.ToLower(profile.GpuManufacturer.Values[0])
+			}
+			capacity[corev1.ResourceName(manufacturer+".com/gpu")] = *resource.NewQuantity(count, resource.DecimalSI)
+		}
+	}
+	return capacity
+}
+
+func profileValue(value, defaultValue *int64) int64 {
+	if value != nil {
+		return *value
+	} else if defaultValue != nil {
+		return *defaultValue
+	}
+	return 0
+}
+
+// PatchObject persists the machine template configuration and status.
+func (m *MachineTemplateScope) PatchObject() error {
+	return m.patchHelper.Patch(context.TODO(), m.IBMVPCMachineTemplate)
+}
+
+// Close closes the current scope persisting the machine template configuration and status.
+func (m *MachineTemplateScope) Close() error {
+	return m.PatchObject()
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"path"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+)
+
+func TestReconcileCapacity(t *testing.T) {
+	profiles := map[string]string{
+		"bx2-4x16": `{
+			"name": "bx2-4x16",
+			"vcpu_count": {"type": "fixed", "value": 4},
+			"memory": {"type": "fixed", "value": 16}
+		}`,
+		"gx2-16x128x2v100": `{
+			"name": "gx2-16x128x2v100",
+			"vcpu_count": {"type": "fixed", "value": 16},
+			"memory": {"type": "fixed", "value": 128},
+			"gpu_count": {"type": "fixed", "value": 2},
+			"gpu_manufacturer": {"type": "enum", "values": ["NVIDIA"]}
+		}`,
+		"gx3-8x64x1amd": `{
+			"name": "gx3-8x64x1amd",
+			"vcpu_count": {"type": "range", "default": 8, "min": 2, "max": 16, "step": 2},
+			"memory": {"type": "range", "default": 64, "min": 16, "max": 128, "step": 16},
+			"gpu_count": {"type": "fixed", "value": 1},
+			"gpu_manufacturer": {"type": "enum", "values": ["AMD"]}
+		}`,
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := profiles[path.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(profile))
+	}
+
+	tests := []struct {
+		name         string
+		profile      string
+		wantCapacity corev1.ResourceList
+	}{
+		{
+			name:    "profile without GPUs",
+			profile: "bx2-4x16",
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+		{
+			name:    "profile with NVIDIA GPUs",
+			profile: "gx2-16x128x2v100",
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("128Gi"),
+				"nvidia.com/gpu":      resource.MustParse("2"),
+			},
+		},
+		{
+			name:    "configurable profile with AMD GPUs reports the defaults",
+			profile: "gx3-8x64x1amd",
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+				"amd.com/gpu":         resource.MustParse("1"),
+			},
+		},
+		{
+			name:    "missing profile",
+			profile: "bx2-missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scope := &MachineTemplateScope{
+				Logger:        klogr.New(),
+				IBMVPCClients: newFakeVPCClients(t, handler),
+				IBMVPCMachineTemplate: &infrav1.IBMVPCMachineTemplate{
+					Spec: infrav1.IBMVPCMachineTemplateSpec{
+						Template: infrav1.IBMVPCMachineTemplateResource{
+							Spec: infrav1.IBMVPCMachineSpec{Profile: tt.profile},
+						},
+					},
+				},
+			}
+			g.Expect(scope.ReconcileCapacity()).To(Succeed())
+
+			template := scope.IBMVPCMachineTemplate
+			if tt.wantCapacity == nil {
+				g.Expect(template.Status.Capacity).To(BeNil())
+				g.Expect(conditions.IsFalse(template, infrav1.InstanceProfileValidCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(template, infrav1.InstanceProfileValidCondition)).To(Equal(infrav1.InstanceProfileNotFoundReason))
+				return
+			}
+			g.Expect(template.Status.Capacity).To(HaveLen(len(tt.wantCapacity)))
+			for name, quantity := range tt.wantCapacity {
+				got, ok := template.Status.Capacity[name]
+				g.Expect(ok).To(BeTrue(), "capacity %s", name)
+				g.Expect(got.Cmp(quantity)).To(BeZero(), "capacity %s", name)
+			}
+			g.Expect(conditions.IsTrue(template, infrav1.InstanceProfileValidCondition)).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+)
+
+func newVPCMachineScope(t *testing.T, handler http.HandlerFunc, spec infrav1.IBMVPCMachineSpec) *MachineScope {
+	return &MachineScope{
+		Logger:        klogr.New(),
+		IBMVPCClients: newFakeVPCClients(t, handler),
+		IBMVPCMachine: &infrav1.IBMVPCMachine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "machine"},
+			Spec:       spec,
+		},
+	}
+}
+
+func TestGetVolumeAttachmentPrototypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []infrav1.VPCVolume
+		want    []vpcv1.VolumeAttachmentPrototypeInstanceContext
+	}{
+		{
+			name:    "names the volumes after the machine and defaults the profile",
+			volumes: []infrav1.VPCVolume{{SizeGiB: 10}, {Name: "logs", SizeGiB: 20}},
+			want: []vpcv1.VolumeAttachmentPrototypeInstanceContext{
+				{
+					Name:                         core.StringPtr("machine-data-0"),
+					DeleteVolumeOnInstanceDelete: core.BoolPtr(true),
+					Volume: &vpcv1.VolumeAttachmentVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumeByCapacity{
+						Name:     core.StringPtr("machine-data-0"),
+						Capacity: core.Int64Ptr(10),
+						Profile:  &vpcv1.VolumeProfileIdentityByName{Name: core.StringPtr("general-purpose")},
+					},
+				},
+				{
+					Name:                         core.StringPtr("machine-logs"),
+					DeleteVolumeOnInstanceDelete: core.BoolPtr(true),
+					Volume: &vpcv1.VolumeAttachmentVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumeByCapacity{
+						Name:     core.StringPtr("machine-logs"),
+						Capacity: core.Int64Ptr(20),
+						Profile:  &vpcv1.VolumeProfileIdentityByName{Name: core.StringPtr("general-purpose")},
+					},
+				},
+			},
+		},
+		{
+			name: "sets the iops, encryption key and delete policy",
+			volumes: []infrav1.VPCVolume{{
+				Name: "db", SizeGiB: 100, Profile: "custom", Iops: 1000, EncryptionKeyCRN: "crn:key", DeleteOnInstanceDelete: core.BoolPtr(false),
+			}},
+			want: []vpcv1.VolumeAttachmentPrototypeInstanceContext{{
+				Name:                         core.StringPtr("machine-db"),
+				DeleteVolumeOnInstanceDelete: core.BoolPtr(false),
+				Volume: &vpcv1.VolumeAttachmentVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumePrototypeInstanceContextVolumeByCapacity{
+					Name:          core.StringPtr("machine-db"),
+					Capacity:      core.Int64Ptr(100),
+					Profile:       &vpcv1.VolumeProfileIdentityByName{Name: core.StringPtr("custom")},
+					Iops:          core.Int64Ptr(1000),
+					EncryptionKey: &vpcv1.EncryptionKeyIdentityByCRN{CRN: core.StringPtr("crn:key")},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newVPCMachineScope(t, http.NotFound, infrav1.IBMVPCMachineSpec{DataVolumes: tt.volumes})
+			g.Expect(m.getVolumeAttachmentPrototypes()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetDataVolumesStatus(t *testing.T) {
+	attachment := func(id, volumeID, volumeName string) vpcv1.VolumeAttachmentReferenceInstanceContext {
+		return vpcv1.VolumeAttachmentReferenceInstanceContext{
+			ID:     core.StringPtr(id),
+			Volume: &vpcv1.VolumeReference{ID: core.StringPtr(volumeID), Name: core.StringPtr(volumeName)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		instance *vpcv1.Instance
+		want     []infrav1.VPCVolumeStatus
+	}{
+		{
+			name: "skips the boot volume",
+			instance: &vpcv1.Instance{
+				BootVolumeAttachment: &vpcv1.VolumeAttachmentReferenceInstanceContext{ID: core.StringPtr("boot")},
+				VolumeAttachments: []vpcv1.VolumeAttachmentReferenceInstanceContext{
+					attachment("boot", "boot-volume", "machine-boot"),
+					attachment("data", "data-volume", "machine-data-0"),
+				},
+			},
+			want: []infrav1.VPCVolumeStatus{{Name: "machine-data-0", ID: "data-volume", AttachmentID: "data"}},
+		},
+		{
+			name: "skips the attachments without volume",
+			instance: &vpcv1.Instance{
+				VolumeAttachments: []vpcv1.VolumeAttachmentReferenceInstanceContext{
+					{ID: core.StringPtr("pending")},
+					attachment("logs", "logs-volume", "machine-logs"),
+				},
+			},
+			want: []infrav1.VPCVolumeStatus{{Name: "machine-logs", ID: "logs-volume", AttachmentID: "logs"}},
+		},
+		{
+			name:     "returns nothing without data volumes",
+			instance: &vpcv1.Instance{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newVPCMachineScope(t, http.NotFound, infrav1.IBMVPCMachineSpec{})
+			g.Expect(m.GetDataVolumesStatus(tt.instance)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetPlacementTarget(t *testing.T) {
+	dedicatedHost := func(zone string, enabled bool, profiles ...string) map[string]interface{} {
+		supported := []map[string]string{}
+		for _, profile := range profiles {
+			supported = append(supported, map[string]string{"name": profile})
+		}
+		return map[string]interface{}{
+			"id": "host", "name": "host", "zone": map[string]string{"name": zone},
+			"instance_placement_enabled": enabled, "supported_instance_profiles": supported,
+		}
+	}
+	dedicatedHostGroup := func(family string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": "group", "name": "group", "family": family, "zone": map[string]string{"name": "us-south-1"},
+			"supported_instance_profiles": []map[string]string{{"name": "bx2-4x16"}},
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		spec                 infrav1.IBMVPCMachineSpec
+		controlPlane         bool
+		host                 map[string]interface{}
+		group                map[string]interface{}
+		want                 vpcv1.InstancePlacementTargetPrototypeIntf
+		wantPlacementGroupID string
+		wantErr              string
+	}{
+		{
+			name: "does not place a worker machine without placement target",
+		},
+		{
+			name:                 "places a control plane machine in the placement group of the cluster",
+			controlPlane:         true,
+			want:                 &vpcv1.InstancePlacementTargetPrototypePlacementGroupIdentity{ID: core.StringPtr("control-plane-pg")},
+			wantPlacementGroupID: "control-plane-pg",
+		},
+		{
+			name:                 "places the machine in the placement group of the spec",
+			spec:                 infrav1.IBMVPCMachineSpec{PlacementGroup: &infrav1.IBMVPCResourceReference{Name: core.StringPtr("workers")}},
+			controlPlane:         true,
+			want:                 &vpcv1.InstancePlacementTargetPrototypePlacementGroupIdentity{ID: core.StringPtr("pg-2")},
+			wantPlacementGroupID: "pg-2",
+		},
+		{
+			name: "places the machine on the dedicated host",
+			spec: infrav1.IBMVPCMachineSpec{DedicatedHost: &infrav1.IBMVPCResourceReference{ID: core.StringPtr("host")}},
+			host: dedicatedHost("us-south-1", true, "bx2-4x16"),
+			want: &vpcv1.InstancePlacementTargetPrototypeDedicatedHostIdentity{ID: core.StringPtr("host")},
+		},
+		{
+			name:    "rejects a dedicated host with instance placement disabled",
+			spec:    infrav1.IBMVPCMachineSpec{DedicatedHost: &infrav1.IBMVPCResourceReference{ID: core.StringPtr("host")}},
+			host:    dedicatedHost("us-south-1", false, "bx2-4x16"),
+			wantErr: "instance placement is disabled on dedicated host host",
+		},
+		{
+			name:    "rejects a dedicated host in another zone",
+			spec:    infrav1.IBMVPCMachineSpec{DedicatedHost: &infrav1.IBMVPCResourceReference{ID: core.StringPtr("host")}},
+			host:    dedicatedHost("us-south-2", true, "bx2-4x16"),
+			wantErr: "dedicated host host is not in zone us-south-1",
+		},
+		{
+			name:    "rejects a dedicated host not supporting the profile",
+			spec:    infrav1.IBMVPCMachineSpec{DedicatedHost: &infrav1.IBMVPCResourceReference{ID: core.StringPtr("host")}},
+			host:    dedicatedHost("us-south-1", true, "mx2-2x16"),
+			wantErr: "dedicated host host does not support instance profile bx2-4x16",
+		},
+		{
+			name:  "places the machine in the dedicated host group",
+			spec:  infrav1.IBMVPCMachineSpec{DedicatedHostGroup: &infrav1.IBMVPCResourceReference{Name: core.StringPtr("group")}},
+			group: dedicatedHostGroup("balanced"),
+			want:  &vpcv1.InstancePlacementTargetPrototypeDedicatedHostGroupIdentity{ID: core.StringPtr("group")},
+		},
+		{
+			name:    "rejects a dedicated host group of another profile family",
+			spec:    infrav1.IBMVPCMachineSpec{DedicatedHostGroup: &infrav1.IBMVPCResourceReference{Name: core.StringPtr("group")}},
+			group:   dedicatedHostGroup("memory"),
+			wantErr: "instance profile bx2-4x16 of family balanced can't be placed in dedicated host group group of family memory",
+		},
+		{
+			name: "rejects several placement targets",
+			spec: infrav1.IBMVPCMachineSpec{
+				PlacementGroup: &infrav1.IBMVPCResourceReference{Name: core.StringPtr("workers")},
+				DedicatedHost:  &infrav1.IBMVPCResourceReference{ID: core.StringPtr("host")},
+			},
+			host:    dedicatedHost("us-south-1", true, "bx2-4x16"),
+			wantErr: "only one of placementGroup, dedicatedHost and dedicatedHostGroup can be specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resources := map[string]interface{}{
+				"/dedicated_hosts/host":       tt.host,
+				"/instance/profiles/bx2-4x16": map[string]string{"name": "bx2-4x16", "family": "balanced"},
+			}
+			collections := fakeVPCCollections{
+				"/placement_groups":      {vpcResource("pg-1", "control-plane"), vpcResource("pg-2", "workers")},
+				"/dedicated_host/groups": {},
+			}
+			if tt.group != nil {
+				collections["/dedicated_host/groups"] = []map[string]interface{}{tt.group}
+			}
+			spec := tt.spec
+			spec.Zone = "us-south-1"
+			spec.Profile = "bx2-4x16"
+			m := newVPCMachineScope(t, func(w http.ResponseWriter, r *http.Request) {
+				if resource, ok := resources[r.URL.Path]; ok {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(resource)
+					return
+				}
+				collections.ServeHTTP(w, r)
+			}, spec)
+			m.IBMVPCCluster = &infrav1.IBMVPCCluster{Status: infrav1.IBMVPCClusterStatus{ControlPlanePlacementGroupID: "control-plane-pg"}}
+			if tt.controlPlane {
+				m.IBMVPCMachine.Labels = map[string]string{clusterv1.MachineControlPlaneLabelName: ""}
+			}
+
+			target, err := m.getPlacementTarget()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.want == nil {
+				g.Expect(target).To(BeNil())
+			} else {
+				g.Expect(target).To(Equal(tt.want))
+			}
+			g.Expect(m.IBMVPCMachine.Status.PlacementGroupID).To(Equal(tt.wantPlacementGroupID))
+		})
+	}
+}
+
+func TestSetInstanceState(t *testing.T) {
+	tests := []struct {
+		status       string
+		reasons      []vpcv1.InstanceStatusReason
+		wantReady    bool
+		wantReason   string
+		wantSeverity clusterv1.ConditionSeverity
+		wantMessage  string
+	}{
+		{
+			status:    vpcv1.InstanceStatusRunningConst,
+			wantReady: true,
+		},
+		{
+			status:       vpcv1.InstanceStatusPendingConst,
+			wantReason:   infrav1.InstancePendingReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "instance instance is pending",
+		},
+		{
+			status:       vpcv1.InstanceStatusStartingConst,
+			wantReason:   infrav1.InstancePendingReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "instance instance is starting",
+		},
+		{
+			status:       vpcv1.InstanceStatusRestartingConst,
+			wantReason:   infrav1.InstancePendingReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "instance instance is restarting",
+		},
+		{
+			status:       vpcv1.InstanceStatusResumingConst,
+			wantReason:   infrav1.InstancePendingReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "instance instance is resuming",
+		},
+		{
+			status:       vpcv1.InstanceStatusStoppingConst,
+			wantReason:   infrav1.InstanceStoppedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "instance instance is stopping",
+		},
+		{
+			status:       vpcv1.InstanceStatusStoppedConst,
+			wantReason:   infrav1.InstanceStoppedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "instance instance is stopped",
+		},
+		{
+			status:       vpcv1.InstanceStatusPausingConst,
+			wantReason:   infrav1.InstanceStoppedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "instance instance is pausing",
+		},
+		{
+			status:       vpcv1.InstanceStatusPausedConst,
+			wantReason:   infrav1.InstanceStoppedReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "instance instance is paused",
+		},
+		{
+			status: vpcv1.InstanceStatusFailedConst,
+			reasons: []vpcv1.InstanceStatusReason{
+				{Code: core.StringPtr("cannot_start_capacity"), Message: core.StringPtr("insufficient capacity")},
+			},
+			wantReason:   infrav1.InstanceFailedReason,
+			wantSeverity: clusterv1.ConditionSeverityError,
+			wantMessage:  "instance instance is failed: insufficient capacity",
+		},
+		{
+			status:       vpcv1.InstanceStatusDeletingConst,
+			wantReason:   infrav1.InstanceDeletingReason,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "instance instance is being deleted",
+		},
+		{
+			status:       "migrating",
+			wantReason:   infrav1.InstancePendingReason,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "instance instance is in migrating state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newVPCMachineScope(t, http.NotFound, infrav1.IBMVPCMachineSpec{})
+
+			m.SetInstanceState(&vpcv1.Instance{ID: core.StringPtr("instance"), Status: core.StringPtr(tt.status), StatusReasons: tt.reasons})
+			g.Expect(m.IBMVPCMachine.Status.InstanceStatus).To(Equal(tt.status))
+			g.Expect(m.IBMVPCMachine.Status.Ready).To(Equal(tt.wantReady))
+			condition := conditions.Get(m.IBMVPCMachine, infrav1.InstanceReadyCondition)
+			g.Expect(condition).NotTo(BeNil())
+			if tt.wantReady {
+				g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+				return
+			}
+			g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(condition.Reason).To(Equal(tt.wantReason))
+			g.Expect(condition.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(condition.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
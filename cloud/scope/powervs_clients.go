@@ -17,10 +17,14 @@ limitations under the License.
 package scope
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/IBM-Cloud/power-go-client/clients/instance"
 	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	utils "github.com/ppc64le-cloud/powervs-utils"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
 )
 
 // TIMEOUT is used while creating IBM Power VS client
@@ -28,10 +32,18 @@ const TIMEOUT = 1 * time.Hour
 
 // IBMPowerVSClient used to store IBM Power VS client information
 type IBMPowerVSClient struct {
-	session        *ibmpisession.IBMPISession
-	InstanceClient *instance.IBMPIInstanceClient
-	NetworkClient  *instance.IBMPINetworkClient
-	ImageClient    *instance.IBMPIImageClient
+	session              *ibmpisession.IBMPISession
+	InstanceClient       *instance.IBMPIInstanceClient
+	NetworkClient        *instance.IBMPINetworkClient
+	ImageClient          *instance.IBMPIImageClient
+	DHCPClient           *instance.IBMPIDhcpClient
+	JobClient            *instance.IBMPIJobClient
+	VolumeClient         *instance.IBMPIVolumeClient
+	PlacementGroupClient *instance.IBMPIPlacementGroupClient
+	SystemPoolClient     *instance.IBMPISystemPoolClient
+	StorageClient        *instance.IBMPIStorageCapacityClient
+	// SharedProcessorPoolClient manages shared processor pools, which the power-go-client does not support yet.
+	SharedProcessorPoolClient *SharedProcessorPoolClient
 }
 
 // NewIBMPowerVSClient creates and returns a IBM Power VS client
@@ -45,5 +57,31 @@ func NewIBMPowerVSClient(token, account, cloudInstanceID, region, zone string, d
 	client.InstanceClient = instance.NewIBMPIInstanceClient(client.session, cloudInstanceID)
 	client.NetworkClient = instance.NewIBMPINetworkClient(client.session, cloudInstanceID)
 	client.ImageClient = instance.NewIBMPIImageClient(client.session, cloudInstanceID)
+	client.DHCPClient = instance.NewIBMPIDhcpClient(client.session, cloudInstanceID)
+	client.JobClient = instance.NewIBMPIJobClient(client.session, cloudInstanceID)
+	client.VolumeClient = instance.NewIBMPIVolumeClient(client.session, cloudInstanceID)
+	client.PlacementGroupClient = instance.NewIBMPIPlacementGroupClient(client.session, cloudInstanceID)
+	client.SystemPoolClient = instance.NewIBMPISystemPoolClient(client.session, cloudInstanceID)
+	client.StorageClient = instance.NewIBMPIStorageCapacityClient(client.session, cloudInstanceID)
+	client.SharedProcessorPoolClient = NewSharedProcessorPoolClient(client.session)
 	return client, nil
 }
+
+// newIBMPowerVSClientForServiceInstance creates and returns a IBM Power VS client for the region and zone of a service instance
+func newIBMPowerVSClientForServiceInstance(client *pkg.Client, serviceInstanceID string) (*IBMPowerVSClient, error) {
+	resource, err := client.ResourceClient.GetInstance(serviceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	region, err := utils.GetRegion(resource.RegionID)
+	if err != nil {
+		return nil, err
+	}
+	zone := resource.RegionID
+
+	c, err := NewIBMPowerVSClient(client.Config.IAMAccessToken, client.User.Account, serviceInstanceID, region, zone, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NewIBMPowerVSClient")
+	}
+	return c, nil
+}
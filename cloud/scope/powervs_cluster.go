@@ -18,14 +18,28 @@ package scope
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
-	utils "github.com/ppc64le-cloud/powervs-utils"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev1/controller"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/managementv2"
+	"github.com/IBM-Cloud/bluemix-go/bmxerror"
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_networks"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_placement_groups"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_tenants_ssh_keys"
+	"github.com/IBM-Cloud/power-go-client/power/models"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,8 +59,9 @@ type PowerVSClusterScopeParams struct {
 // PowerVSClusterScope defines a scope defined around a Power VS Cluster.
 type PowerVSClusterScope struct {
 	logr.Logger
-	client      client.Client
-	patchHelper *patch.Helper
+	client         client.Client
+	patchHelper    *patch.Helper
+	ibmCloudClient *pkg.Client
 
 	IBMPowerVSClient  *IBMPowerVSClient
 	Cluster           *clusterv1.Cluster
@@ -66,37 +81,41 @@ func NewPowerVSClusterScope(params PowerVSClusterScopeParams) (*PowerVSClusterSc
 		params.Logger = klogr.New()
 	}
 
-	client := pkg.NewClient()
-
-	spec := params.IBMPowerVSCluster.Spec
-	resource, err := client.ResourceClient.GetInstance(spec.ServiceInstanceID)
-	if err != nil {
-		return nil, err
-	}
-	region, err := utils.GetRegion(resource.RegionID)
-	if err != nil {
-		return nil, err
-	}
-	zone := resource.RegionID
-
-	c, err := NewIBMPowerVSClient(client.Config.IAMAccessToken, client.User.Account, spec.ServiceInstanceID, region, zone, true)
+	helper, err := patch.NewHelper(params.IBMPowerVSCluster, params.Client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create NewIBMPowerVSClient")
+		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
 
-	helper, err := patch.NewHelper(params.IBMPowerVSCluster, params.Client)
+	ibmCloudClient, err := pkg.NewClient()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to init patch helper")
+		return nil, errors.Wrap(err, "failed to create IBM Cloud client")
 	}
 
-	return &PowerVSClusterScope{
+	scope := &PowerVSClusterScope{
 		Logger:            params.Logger,
 		client:            params.Client,
-		IBMPowerVSClient:  c,
+		ibmCloudClient:    ibmCloudClient,
 		Cluster:           params.Cluster,
 		IBMPowerVSCluster: params.IBMPowerVSCluster,
 		patchHelper:       helper,
-	}, nil
+	}
+
+	// The Power VS client is created once the service instance of the cluster exists.
+	if serviceInstanceID := params.IBMPowerVSCluster.GetServiceInstanceID(); serviceInstanceID != "" {
+		if err := scope.setIBMPowerVSClient(serviceInstanceID); err != nil {
+			return nil, err
+		}
+	}
+	return scope, nil
+}
+
+func (s *PowerVSClusterScope) setIBMPowerVSClient(serviceInstanceID string) error {
+	c, err := newIBMPowerVSClientForServiceInstance(s.ibmCloudClient, serviceInstanceID)
+	if err != nil {
+		return err
+	}
+	s.IBMPowerVSClient = c
+	return nil
 }
 
 // PatchObject persists the cluster configuration and status.
@@ -108,3 +127,947 @@ func (s *PowerVSClusterScope) PatchObject() error {
 func (s *PowerVSClusterScope) Close() error {
 	return s.PatchObject()
 }
+
+const (
+	// powerVSServiceName is the name of the Power Systems Virtual Server service in the resource catalog.
+	powerVSServiceName = "power-iaas"
+	// powerVSServicePlanName is the name of the plan of the Power VS service instances.
+	powerVSServicePlanName = "power-virtual-server-group"
+
+	serviceInstanceStateActive       = "active"
+	serviceInstanceStateProvisioning = "provisioning"
+)
+
+// ReconcileServiceInstance creates the service instance of the cluster unless it is referenced in the spec,
+// and records it in the status. It returns whether the service instance is active.
+func (s *PowerVSClusterScope) ReconcileServiceInstance() (bool, error) {
+	spec := s.IBMPowerVSCluster.Spec
+	if spec.ServiceInstanceID != "" {
+		if spec.ServiceInstance != nil {
+			return false, fmt.Errorf("only one of serviceInstanceID and serviceInstance can be specified")
+		}
+		return true, nil
+	}
+	if spec.ServiceInstance == nil {
+		return false, fmt.Errorf("either serviceInstanceID or serviceInstance must be specified")
+	}
+
+	status := s.IBMPowerVSCluster.Status.ServiceInstance
+	if status == nil || status.ID == "" {
+		id, err := s.createServiceInstance()
+		if err != nil {
+			return false, err
+		}
+		status = &v1alpha4.PowerVSServiceInstanceStatus{ID: id, ControllerCreated: true}
+		s.IBMPowerVSCluster.Status.ServiceInstance = status
+	}
+
+	instance, err := s.ibmCloudClient.ResourceClient.GetInstance(status.ID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get service instance %s", status.ID)
+	}
+	status.State = instance.State
+	switch instance.State {
+	case serviceInstanceStateActive:
+	case serviceInstanceStateProvisioning:
+		s.Info("Service instance is not active yet", "id", status.ID)
+		return false, nil
+	default:
+		return false, fmt.Errorf("service instance %s is in state %s", status.ID, instance.State)
+	}
+
+	if s.IBMPowerVSClient == nil {
+		if err := s.setIBMPowerVSClient(status.ID); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// createServiceInstance creates the service instance of the cluster and returns its GUID. An existing service
+// instance of the same name is not adopted, it can't be told apart from one created outside the controller,
+// which must be referenced with the service instance ID instead.
+func (s *PowerVSClusterScope) createServiceInstance() (string, error) {
+	serviceInstance := s.IBMPowerVSCluster.Spec.ServiceInstance
+	name := serviceInstance.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-serviceinstance", s.Cluster.Name)
+	}
+	resourceGroupID, err := s.getResourceGroupID(serviceInstance.ResourceGroup)
+	if err != nil {
+		return "", err
+	}
+
+	catalogClient := s.ibmCloudClient.ResourceCatalogClient
+	serviceID, err := catalogClient.GetServiceID(powerVSServiceName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get service %s from the resource catalog", powerVSServiceName)
+	}
+
+	instances, err := s.ibmCloudClient.ResourceControllerClient.ListInstances(controller.ServiceInstanceQuery{
+		ResourceGroupID: resourceGroupID,
+		ServiceID:       serviceID,
+		Name:            name,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list service instances")
+	}
+	if len(instances) > 0 {
+		return "", fmt.Errorf("service instance %s already exists in resource group %s, set serviceInstanceID to use it", name, resourceGroupID)
+	}
+
+	service, err := catalogClient.Get(serviceID, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get service %s from the resource catalog", powerVSServiceName)
+	}
+	planID, err := catalogClient.GetServicePlanID(service, powerVSServicePlanName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get plan %s of service %s", powerVSServicePlanName, powerVSServiceName)
+	}
+	deployments, err := catalogClient.ListDeployments(planID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list deployments of plan %s", powerVSServicePlanName)
+	}
+	var targetCRN string
+	for _, deployment := range deployments {
+		if deployment.Metadata.Deployment.Location == serviceInstance.Zone {
+			targetCRN = deployment.CatalogCRN
+			break
+		}
+	}
+	if targetCRN == "" {
+		return "", fmt.Errorf("service %s is not available in zone %s", powerVSServiceName, serviceInstance.Zone)
+	}
+
+	instance, err := s.ibmCloudClient.ResourceControllerClient.CreateInstance(controller.CreateServiceInstanceRequest{
+		Name:            name,
+		ServicePlanID:   planID,
+		ResourceGroupID: resourceGroupID,
+		TargetCrn:       targetCRN,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create service instance")
+	}
+	s.Info("Created service instance", "name", name, "id", instance.Guid)
+	return instance.Guid, nil
+}
+
+func (s *PowerVSClusterScope) getResourceGroupID(ref v1alpha4.IBMPowerVSResourceReference) (string, error) {
+	if ref.ID != nil {
+		return *ref.ID, nil
+	}
+	if ref.Name == nil {
+		return "", fmt.Errorf("both ID and Name of the resource group can't be nil")
+	}
+	query := &managementv2.ResourceGroupQuery{AccountID: s.ibmCloudClient.User.Account}
+	groups, err := s.ibmCloudClient.ResourceGroupClient.FindByName(query, *ref.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find resource group %s", *ref.Name)
+	}
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	id, err := uniqueResourceID("resource group", *ref.Name, ids)
+	if err != nil {
+		return "", err
+	}
+	return *id, nil
+}
+
+// DeleteServiceInstance deletes the service instance of the cluster if it was created by the controller,
+// the resources remaining in the service instance are deleted with it.
+func (s *PowerVSClusterScope) DeleteServiceInstance() error {
+	status := s.IBMPowerVSCluster.Status.ServiceInstance
+	if status == nil || !status.ControllerCreated {
+		return nil
+	}
+	if err := s.ibmCloudClient.ResourceControllerClient.DeleteInstance(status.ID, true); err != nil {
+		if apiErr, ok := err.(bmxerror.RequestFailure); !ok || apiErr.StatusCode() != http.StatusNotFound {
+			return errors.Wrapf(err, "failed to delete service instance %s", status.ID)
+		}
+	}
+	s.Info("Deleted service instance", "id", status.ID)
+	s.IBMPowerVSCluster.Status.ServiceInstance = nil
+	return nil
+}
+
+// ReconcileNetwork verifies the network referenced in the spec or creates the private network of the cluster,
+// and records it in the status. It returns whether the network is ready.
+func (s *PowerVSClusterScope) ReconcileNetwork() (bool, error) {
+	spec := s.IBMPowerVSCluster.Spec
+	if spec.PrivateNetwork == nil {
+		ref, err := s.getNetwork(spec.Network)
+		if err != nil {
+			return false, err
+		}
+		network, err := s.IBMPowerVSClient.NetworkClient.Get(*ref.NetworkID, s.IBMPowerVSCluster.GetServiceInstanceID(), TIMEOUT)
+		if err != nil {
+			return false, err
+		}
+		s.IBMPowerVSCluster.Status.Network = &v1alpha4.PowerVSNetworkStatus{
+			ID:   *network.NetworkID,
+			Name: *network.Name,
+			CIDR: *network.Cidr,
+		}
+		return true, nil
+	}
+
+	if spec.Network.ID != nil || spec.Network.Name != nil {
+		return false, fmt.Errorf("only one of network and privateNetwork can be specified")
+	}
+	if err := validatePrivateNetwork(spec.PrivateNetwork); err != nil {
+		return false, err
+	}
+	if spec.PrivateNetwork.DHCP {
+		return s.reconcileDHCPNetwork()
+	}
+	return s.reconcileStaticNetwork()
+}
+
+// validatePrivateNetwork rejects the settings the DHCP server of the service instance chooses itself.
+func validatePrivateNetwork(privateNetwork *v1alpha4.PowerVSPrivateNetwork) error {
+	if !privateNetwork.DHCP {
+		if privateNetwork.CIDR == "" {
+			return fmt.Errorf("cidr of the private network is required unless dhcp is set")
+		}
+		return nil
+	}
+	var fields []string
+	if privateNetwork.CIDR != "" {
+		fields = append(fields, "cidr")
+	}
+	if privateNetwork.Gateway != "" {
+		fields = append(fields, "gateway")
+	}
+	if privateNetwork.StartIP != "" || privateNetwork.EndIP != "" {
+		fields = append(fields, "startIP and endIP")
+	}
+	if len(privateNetwork.DNSServers) > 0 {
+		fields = append(fields, "dnsServers")
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("%s of the private network can't be specified with dhcp, they are chosen by the DHCP server", strings.Join(fields, ", "))
+	}
+	return nil
+}
+
+// reconcileDHCPNetwork creates a DHCP server and its private network, the network is ready once the server is active.
+func (s *PowerVSClusterScope) reconcileDHCPNetwork() (bool, error) {
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	status := s.IBMPowerVSCluster.Status.Network
+	if status == nil || status.DHCPServerID == "" {
+		// The service instance has a single DHCP server, an existing server is used without being owned.
+		servers, err := s.IBMPowerVSClient.DHCPClient.GetAll(serviceInstanceID)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to list DHCP servers")
+		}
+		if len(servers) > 0 {
+			status = &v1alpha4.PowerVSNetworkStatus{DHCPServerID: *servers[0].ID}
+		} else {
+			server, err := s.IBMPowerVSClient.DHCPClient.Create(serviceInstanceID)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to create DHCP server")
+			}
+			s.Info("Created DHCP server", "id", *server.ID)
+			status = &v1alpha4.PowerVSNetworkStatus{DHCPServerID: *server.ID, ControllerCreated: true}
+		}
+		s.IBMPowerVSCluster.Status.Network = status
+	}
+
+	server, err := s.IBMPowerVSClient.DHCPClient.Get(status.DHCPServerID, serviceInstanceID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get DHCP server %s", status.DHCPServerID)
+	}
+	if server.Network != nil && server.Network.ID != nil {
+		status.ID = *server.Network.ID
+		status.Name = *server.Network.Name
+	}
+	if server.Status == nil || *server.Status != "ACTIVE" || status.ID == "" {
+		s.Info("DHCP server is not active yet", "id", status.DHCPServerID)
+		return false, nil
+	}
+	network, err := s.IBMPowerVSClient.NetworkClient.Get(status.ID, serviceInstanceID, TIMEOUT)
+	if err != nil {
+		return false, err
+	}
+	status.CIDR = *network.Cidr
+	return true, nil
+}
+
+// reconcileStaticNetwork creates a private network with a static IP range.
+func (s *PowerVSClusterScope) reconcileStaticNetwork() (bool, error) {
+	if s.IBMPowerVSCluster.Status.Network != nil && s.IBMPowerVSCluster.Status.Network.ID != "" {
+		return true, nil
+	}
+
+	privateNetwork := s.IBMPowerVSCluster.Spec.PrivateNetwork
+	name := privateNetwork.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-network", s.Cluster.Name)
+	}
+	networks, err := s.getNetworks()
+	if err != nil {
+		return false, err
+	}
+	gateway, startIP, endIP, err := networkRange(privateNetwork.CIDR)
+	if err != nil {
+		return false, err
+	}
+	if privateNetwork.Gateway != "" {
+		gateway = privateNetwork.Gateway
+	}
+	if privateNetwork.StartIP != "" {
+		startIP = privateNetwork.StartIP
+	}
+	if privateNetwork.EndIP != "" {
+		endIP = privateNetwork.EndIP
+	}
+
+	// A network of the same name is used without being owned, it must match the spec.
+	for _, ref := range networks.Networks {
+		if *ref.Name != name {
+			continue
+		}
+		network, err := s.IBMPowerVSClient.NetworkClient.Get(*ref.NetworkID, s.IBMPowerVSCluster.GetServiceInstanceID(), TIMEOUT)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get network %s", name)
+		}
+		if err := validateNetwork(network, privateNetwork.CIDR, gateway, startIP, endIP); err != nil {
+			return false, errors.Wrapf(err, "network %s can't be used as the private network", name)
+		}
+		s.IBMPowerVSCluster.Status.Network = &v1alpha4.PowerVSNetworkStatus{
+			ID:   *network.NetworkID,
+			Name: name,
+			CIDR: *network.Cidr,
+		}
+		return true, nil
+	}
+
+	network, err := s.IBMPowerVSClient.NetworkClient.Create(name, "vlan", privateNetwork.CIDR, privateNetwork.DNSServers, gateway, startIP, endIP,
+		privateNetwork.Jumbo, s.IBMPowerVSCluster.GetServiceInstanceID(), TIMEOUT)
+	if err != nil {
+		return false, err
+	}
+	s.Info("Created network", "name", name, "id", *network.NetworkID)
+	s.IBMPowerVSCluster.Status.Network = &v1alpha4.PowerVSNetworkStatus{
+		ID:                *network.NetworkID,
+		Name:              name,
+		CIDR:              privateNetwork.CIDR,
+		ControllerCreated: true,
+	}
+	return true, nil
+}
+
+// validateNetwork returns an error when the CIDR, gateway or IP range of an existing network differ from the expected ones.
+func validateNetwork(network *models.Network, cidr, gateway, startIP, endIP string) error {
+	if network.Cidr == nil || *network.Cidr != cidr {
+		return fmt.Errorf("cidr %s differs from %s", pointer.StringDeref(network.Cidr, ""), cidr)
+	}
+	if network.Gateway != gateway {
+		return fmt.Errorf("gateway %s differs from %s", network.Gateway, gateway)
+	}
+	for _, r := range network.IPAddressRanges {
+		if r != nil && pointer.StringDeref(r.StartingIPAddress, "") == startIP && pointer.StringDeref(r.EndingIPAddress, "") == endIP {
+			return nil
+		}
+	}
+	return fmt.Errorf("ip address ranges differ from %s-%s", startIP, endIP)
+}
+
+// DeleteNetwork deletes the network of the cluster if it was created by the controller.
+func (s *PowerVSClusterScope) DeleteNetwork() error {
+	status := s.IBMPowerVSCluster.Status.Network
+	if status == nil || !status.ControllerCreated {
+		return nil
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	if status.DHCPServerID != "" {
+		// Deleting the DHCP server deletes its network.
+		if _, err := s.IBMPowerVSClient.DHCPClient.Delete(status.DHCPServerID, serviceInstanceID); err != nil {
+			return errors.Wrapf(err, "failed to delete DHCP server %s", status.DHCPServerID)
+		}
+	} else if err := s.IBMPowerVSClient.NetworkClient.Delete(status.ID, serviceInstanceID, TIMEOUT); err != nil {
+		return err
+	}
+	s.IBMPowerVSCluster.Status.Network = nil
+	return nil
+}
+
+// defaultAPIServerPort is the port of the API server when the virtual IP does not set one.
+const defaultAPIServerPort = 6443
+
+// ReconcileControlPlaneVIP reserves the virtual IP of the control plane as a port of the cluster network, and of a
+// public network when the virtual IP is public, and populates the control plane endpoint from them. It returns
+// whether the endpoint is known.
+func (s *PowerVSClusterScope) ReconcileControlPlaneVIP() (bool, error) {
+	vip := s.IBMPowerVSCluster.Spec.ControlPlaneVIP
+	if vip == nil {
+		return true, nil
+	}
+	networkID, err := s.getNetworkID()
+	if err != nil {
+		return false, err
+	}
+
+	status := s.IBMPowerVSCluster.Status.ControlPlaneVIP
+	if status == nil {
+		status = &v1alpha4.PowerVSControlPlaneVIPStatus{}
+	}
+	port, err := s.reserveControlPlaneVIPPort(networkID, status.PortID, vip.IPAddress)
+	if err != nil {
+		return false, err
+	}
+	status.PortID = *port.PortID
+	status.IPAddress = *port.IPAddress
+	s.IBMPowerVSCluster.Status.ControlPlaneVIP = status
+
+	host := status.IPAddress
+	if vip.Public {
+		// Only the ports of a public network are assigned an external IP.
+		if status.PublicNetworkID == "" {
+			publicNetworkID, err := s.getPublicNetworkID(vip.PublicNetwork)
+			if err != nil {
+				return false, err
+			}
+			status.PublicNetworkID = publicNetworkID
+		}
+		publicPort, err := s.reserveControlPlaneVIPPort(status.PublicNetworkID, status.PublicPortID, "")
+		if err != nil {
+			return false, err
+		}
+		status.PublicPortID = *publicPort.PortID
+		status.ExternalIP = publicPort.ExternalIP
+		if status.ExternalIP == "" {
+			s.Info("Waiting for the external IP of the control plane virtual IP port", "id", status.PublicPortID)
+			return false, nil
+		}
+		host = status.ExternalIP
+	}
+	endpoint := &s.IBMPowerVSCluster.Spec.ControlPlaneEndpoint
+	if endpoint.Host == "" {
+		endpoint.Host = host
+		endpoint.Port = vip.Port
+		if endpoint.Port == 0 {
+			endpoint.Port = defaultAPIServerPort
+		}
+	}
+	return true, nil
+}
+
+// reserveControlPlaneVIPPort returns the port of the control plane virtual IP on the network. The port is looked up
+// by its id once recorded and by its description otherwise, it is created with the IP address when it does not exist.
+func (s *PowerVSClusterScope) reserveControlPlaneVIPPort(networkID, portID, ipAddress string) (*models.NetworkPort, error) {
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	if portID != "" {
+		return s.IBMPowerVSClient.NetworkClient.GetPort(networkID, serviceInstanceID, portID, TIMEOUT)
+	}
+
+	description := s.controlPlaneVIPDescription()
+	ports, err := s.IBMPowerVSClient.NetworkClient.GetAllPort(networkID, serviceInstanceID, TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range ports.Ports {
+		if port.Description != nil && *port.Description == description {
+			return port, nil
+		}
+	}
+	params := &p_cloud_networks.PcloudNetworksPortsPostParams{
+		Body: &models.NetworkPortCreate{
+			Description: description,
+			IPAddress:   ipAddress,
+		},
+	}
+	port, err := s.IBMPowerVSClient.NetworkClient.CreatePort(networkID, serviceInstanceID, params, TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	s.Info("Created control plane virtual IP port", "network", networkID, "id", *port.PortID, "ip", *port.IPAddress)
+	return port, nil
+}
+
+// powerVSPublicNetworkType is the type of the public networks of a service instance.
+const powerVSPublicNetworkType = "pub-vlan"
+
+// getPublicNetworkID returns the id of the referenced public network, or of the public network of the service
+// instance when the reference is nil.
+func (s *PowerVSClusterScope) getPublicNetworkID(ref *v1alpha4.IBMPowerVSResourceReference) (string, error) {
+	if ref != nil {
+		if ref.ID != nil {
+			return *ref.ID, nil
+		}
+		network, err := s.getNetwork(*ref)
+		if err != nil {
+			return "", err
+		}
+		return *network.NetworkID, nil
+	}
+
+	networks, err := s.getNetworks()
+	if err != nil {
+		return "", err
+	}
+	var ids []string
+	for _, network := range networks.Networks {
+		if network.Type != nil && *network.Type == powerVSPublicNetworkType {
+			ids = append(ids, *network.NetworkID)
+		}
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("service instance %s has no public network for the control plane virtual IP", serviceInstanceID)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("service instance %s has %d public networks, set publicNetwork of the control plane virtual IP", serviceInstanceID, len(ids))
+	}
+}
+
+// DeleteControlPlaneVIP releases the ports reserved for the virtual IP of the control plane.
+func (s *PowerVSClusterScope) DeleteControlPlaneVIP() error {
+	status := s.IBMPowerVSCluster.Status.ControlPlaneVIP
+	if status == nil {
+		return nil
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	if status.PublicPortID != "" {
+		if _, err := s.IBMPowerVSClient.NetworkClient.DeletePort(status.PublicNetworkID, serviceInstanceID, status.PublicPortID, TIMEOUT); err != nil {
+			return errors.Wrapf(err, "failed to delete control plane virtual IP port %s", status.PublicPortID)
+		}
+		s.Info("Deleted control plane virtual IP port", "id", status.PublicPortID)
+		status.PublicPortID = ""
+	}
+	networkID, err := s.getNetworkID()
+	if err != nil {
+		return err
+	}
+	if _, err := s.IBMPowerVSClient.NetworkClient.DeletePort(networkID, serviceInstanceID, status.PortID, TIMEOUT); err != nil {
+		return errors.Wrapf(err, "failed to delete control plane virtual IP port %s", status.PortID)
+	}
+	s.Info("Deleted control plane virtual IP port", "id", status.PortID)
+	s.IBMPowerVSCluster.Status.ControlPlaneVIP = nil
+	return nil
+}
+
+func (s *PowerVSClusterScope) controlPlaneVIPDescription() string {
+	return fmt.Sprintf("%s-control-plane-vip", s.Cluster.Name)
+}
+
+// ReconcilePlacementGroups creates the server placement groups of the cluster and records them in the status.
+// The groups created by the controller and removed from the spec are deleted once they have no members,
+// they are kept in the status until then.
+func (s *PowerVSClusterScope) ReconcilePlacementGroups() error {
+	specs := s.IBMPowerVSCluster.Spec.PlacementGroups
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.Name] = true
+	}
+	recorded := map[string]v1alpha4.PowerVSPlacementGroupStatus{}
+	statuses := make([]v1alpha4.PowerVSPlacementGroupStatus, 0, len(specs))
+	for _, status := range s.IBMPowerVSCluster.Status.PlacementGroups {
+		if wanted[status.Name] {
+			recorded[status.Name] = status
+			continue
+		}
+		deleted, err := s.deletePlacementGroup(status)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			statuses = append(statuses, status)
+		}
+	}
+
+	var existing *models.PlacementGroups
+	for _, spec := range specs {
+		if status, ok := recorded[spec.Name]; ok {
+			statuses = append(statuses, status)
+			continue
+		}
+		if existing == nil {
+			groups, err := s.IBMPowerVSClient.PlacementGroupClient.GetAll(serviceInstanceID)
+			if err != nil {
+				return errors.Wrap(err, "failed to list placement groups")
+			}
+			existing = groups
+		}
+
+		// A group of the same name is used without being owned, unless it was created by a previous reconciliation.
+		var status *v1alpha4.PowerVSPlacementGroupStatus
+		for _, group := range existing.PlacementGroups {
+			if *group.Name == spec.Name {
+				status = &v1alpha4.PowerVSPlacementGroupStatus{Name: spec.Name, ID: *group.ID}
+				break
+			}
+		}
+		if status == nil {
+			params := &p_cloud_placement_groups.PcloudPlacementgroupsPostParams{
+				Body: &models.PlacementGroupCreate{
+					Name:   pointer.StringPtr(spec.Name),
+					Policy: pointer.StringPtr(spec.Policy),
+				},
+			}
+			group, err := s.IBMPowerVSClient.PlacementGroupClient.Create(params, serviceInstanceID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create placement group %s", spec.Name)
+			}
+			s.Info("Created placement group", "name", spec.Name, "id", *group.ID)
+			status = &v1alpha4.PowerVSPlacementGroupStatus{Name: spec.Name, ID: *group.ID, ControllerCreated: true}
+		}
+		statuses = append(statuses, *status)
+	}
+	s.IBMPowerVSCluster.Status.PlacementGroups = statuses
+	return nil
+}
+
+// DeletePlacementGroups deletes the server placement groups created for the cluster once they have no members.
+// It returns whether all of them are deleted.
+func (s *PowerVSClusterScope) DeletePlacementGroups() (bool, error) {
+	var remaining []v1alpha4.PowerVSPlacementGroupStatus
+	for _, status := range s.IBMPowerVSCluster.Status.PlacementGroups {
+		deleted, err := s.deletePlacementGroup(status)
+		if err != nil {
+			return false, err
+		}
+		if !deleted {
+			remaining = append(remaining, status)
+		}
+	}
+	s.IBMPowerVSCluster.Status.PlacementGroups = remaining
+	return len(remaining) == 0, nil
+}
+
+// deletePlacementGroup deletes the placement group if it was created by the controller and has no members.
+// It returns whether the group can be dropped from the status.
+func (s *PowerVSClusterScope) deletePlacementGroup(status v1alpha4.PowerVSPlacementGroupStatus) (bool, error) {
+	if !status.ControllerCreated {
+		return true, nil
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	group, err := s.IBMPowerVSClient.PlacementGroupClient.Get(status.ID, serviceInstanceID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get placement group %s", status.ID)
+	}
+	if len(group.Members) > 0 {
+		s.Info("Placement group still has members", "name", status.Name, "members", group.Members)
+		return false, nil
+	}
+	if err := s.IBMPowerVSClient.PlacementGroupClient.Delete(status.ID, serviceInstanceID); err != nil {
+		return false, errors.Wrapf(err, "failed to delete placement group %s", status.ID)
+	}
+	s.Info("Deleted placement group", "name", status.Name, "id", status.ID)
+	return true, nil
+}
+
+// ReconcileSharedProcessorPool creates the shared processor pool of the cluster, resizes its reserved cores
+// with the spec and records it in the status.
+func (s *PowerVSClusterScope) ReconcileSharedProcessorPool() error {
+	spec := s.IBMPowerVSCluster.Spec.SharedProcessorPool
+	if spec == nil {
+		return nil
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	client := s.IBMPowerVSClient.SharedProcessorPoolClient
+
+	status := s.IBMPowerVSCluster.Status.SharedProcessorPool
+	if status == nil || status.Name != spec.Name {
+		pools, err := client.GetAll(serviceInstanceID)
+		if err != nil {
+			return errors.Wrap(err, "failed to list shared processor pools")
+		}
+		// A pool of the same name is used without being owned, unless it was created by a previous reconciliation.
+		status = nil
+		for _, pool := range pools {
+			if pool.Name == spec.Name {
+				status = &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: spec.Name, ID: pool.ID}
+				break
+			}
+		}
+		if status == nil {
+			pool, err := client.Create(spec.Name, spec.HostGroup, spec.ReservedCores, serviceInstanceID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create shared processor pool %s", spec.Name)
+			}
+			s.Info("Created shared processor pool", "name", spec.Name, "id", pool.ID)
+			status = &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: spec.Name, ID: pool.ID, ControllerCreated: true}
+		}
+	}
+
+	detail, err := client.Get(status.ID, serviceInstanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get shared processor pool %s", status.ID)
+	}
+	if detail == nil || detail.SharedProcessorPool == nil {
+		s.IBMPowerVSCluster.Status.SharedProcessorPool = nil
+		return fmt.Errorf("shared processor pool %s not found", status.ID)
+	}
+	pool := detail.SharedProcessorPool
+	if status.ControllerCreated && pool.ReservedCores != spec.ReservedCores {
+		if pool, err = client.Update(status.ID, spec.ReservedCores, serviceInstanceID); err != nil {
+			return errors.Wrapf(err, "failed to resize shared processor pool %s", spec.Name)
+		}
+		s.Info("Resized shared processor pool", "name", spec.Name, "reservedCores", spec.ReservedCores)
+	}
+	status.ReservedCores = pool.ReservedCores
+	status.State = pool.Status
+	s.IBMPowerVSCluster.Status.SharedProcessorPool = status
+	return nil
+}
+
+// DeleteSharedProcessorPool deletes the shared processor pool created for the cluster once it has no instances.
+// It returns whether it is deleted.
+func (s *PowerVSClusterScope) DeleteSharedProcessorPool() (bool, error) {
+	status := s.IBMPowerVSCluster.Status.SharedProcessorPool
+	if status == nil || !status.ControllerCreated {
+		s.IBMPowerVSCluster.Status.SharedProcessorPool = nil
+		return true, nil
+	}
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	client := s.IBMPowerVSClient.SharedProcessorPoolClient
+	detail, err := client.Get(status.ID, serviceInstanceID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get shared processor pool %s", status.ID)
+	}
+	if detail != nil {
+		if len(detail.Servers) > 0 {
+			s.Info("Shared processor pool still has instances", "name", status.Name, "instances", len(detail.Servers))
+			return false, nil
+		}
+		if err := client.Delete(status.ID, serviceInstanceID); err != nil {
+			return false, errors.Wrapf(err, "failed to delete shared processor pool %s", status.ID)
+		}
+		s.Info("Deleted shared processor pool", "name", status.Name, "id", status.ID)
+	}
+	s.IBMPowerVSCluster.Status.SharedProcessorPool = nil
+	return true, nil
+}
+
+// ReconcileSSHKey registers the public key of the SSH key Secret as a key pair, and updates the key pair
+// when the Secret changes. A key pair of the same name which was not created by the controller is used only
+// when it holds the same key, it is never updated or deleted.
+func (s *PowerVSClusterScope) ReconcileSSHKey() error {
+	ref := s.IBMPowerVSCluster.Spec.SSHKeySecret
+	if ref == nil {
+		return s.DeleteSSHKey()
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: s.IBMPowerVSCluster.Namespace, Name: ref.Name}
+	if err := s.client.Get(context.TODO(), key, secret); err != nil {
+		return errors.Wrapf(err, "failed to retrieve SSH key secret %s", key)
+	}
+	dataKey := ref.Key
+	if dataKey == "" {
+		dataKey = "ssh-publickey"
+	}
+	publicKey := strings.TrimSpace(string(secret.Data[dataKey]))
+	if publicKey == "" {
+		return fmt.Errorf("SSH key secret %s has no key %s", key, dataKey)
+	}
+
+	name := fmt.Sprintf("%s-%s-sshkey", s.Cluster.Namespace, s.Cluster.Name)
+	status := s.IBMPowerVSCluster.Status.SSHKey
+	existing, err := s.getSSHKey(name)
+	if err != nil {
+		return err
+	}
+	session := s.IBMPowerVSClient.session
+	auth := ibmpisession.NewAuth(session, s.IBMPowerVSCluster.GetServiceInstanceID())
+	body := &models.SSHKey{Name: &name, SSHKey: &publicKey}
+	switch {
+	case existing == nil:
+		params := p_cloud_tenants_ssh_keys.NewPcloudTenantsSshkeysPostParamsWithTimeout(TIMEOUT).WithTenantID(session.UserAccount).WithBody(body)
+		if _, _, err := session.Power.PCloudTenantsSSHKeys.PcloudTenantsSshkeysPost(params, auth); err != nil {
+			return errors.Wrapf(err, "failed to create SSH key %s", name)
+		}
+		s.Info("Created SSH key", "name", name)
+		status = &v1alpha4.PowerVSSSHKeyStatus{Name: name, ControllerCreated: true}
+	case existing.SSHKey != nil && *existing.SSHKey == publicKey:
+		if status == nil {
+			status = &v1alpha4.PowerVSSSHKeyStatus{Name: name}
+		}
+	case status == nil || !status.ControllerCreated:
+		return fmt.Errorf("SSH key %s already exists with another key and was not created by the controller", name)
+	default:
+		params := p_cloud_tenants_ssh_keys.NewPcloudTenantsSshkeysPutParamsWithTimeout(TIMEOUT).WithTenantID(session.UserAccount).WithSshkeyName(name).WithBody(body)
+		if _, err := session.Power.PCloudTenantsSSHKeys.PcloudTenantsSshkeysPut(params, auth); err != nil {
+			return errors.Wrapf(err, "failed to update SSH key %s", name)
+		}
+		s.Info("Updated SSH key", "name", name)
+	}
+	s.IBMPowerVSCluster.Status.SSHKey = status
+	return nil
+}
+
+// DeleteSSHKey deletes the key pair registered from the SSH key Secret if it was created by the controller.
+func (s *PowerVSClusterScope) DeleteSSHKey() error {
+	status := s.IBMPowerVSCluster.Status.SSHKey
+	if status == nil {
+		return nil
+	}
+	if status.ControllerCreated {
+		existing, err := s.getSSHKey(status.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			session := s.IBMPowerVSClient.session
+			params := p_cloud_tenants_ssh_keys.NewPcloudTenantsSshkeysDeleteParamsWithTimeout(TIMEOUT).WithTenantID(session.UserAccount).WithSshkeyName(status.Name)
+			if _, err := session.Power.PCloudTenantsSSHKeys.PcloudTenantsSshkeysDelete(params, ibmpisession.NewAuth(session, s.IBMPowerVSCluster.GetServiceInstanceID())); err != nil {
+				return errors.Wrapf(err, "failed to delete SSH key %s", status.Name)
+			}
+			s.Info("Deleted SSH key", "name", status.Name)
+		}
+	}
+	s.IBMPowerVSCluster.Status.SSHKey = nil
+	return nil
+}
+
+// ReconcileBootstrapBucket creates the bucket of the bootstrap data when the cluster delivers it through Cloud Object
+// Storage, a bucket created by the controller is deleted with the cluster.
+func (s *PowerVSClusterScope) ReconcileBootstrapBucket() error {
+	storage := s.IBMPowerVSCluster.Spec.BootstrapStorage
+	if storage == nil {
+		return nil
+	}
+	store, err := newBootstrapDataStore(storage, s.Cluster.Name)
+	if err != nil {
+		return err
+	}
+	if status := s.IBMPowerVSCluster.Status.BootstrapBucket; status != nil && status.Name == store.bucket {
+		return nil
+	}
+
+	created, err := store.EnsureBucket()
+	if err != nil {
+		return errors.Wrapf(err, "failed to ensure bootstrap data bucket %s", store.bucket)
+	}
+	if created {
+		s.Info("Created bootstrap data bucket", "name", store.bucket)
+	}
+	s.IBMPowerVSCluster.Status.BootstrapBucket = &v1alpha4.BootstrapBucketStatus{
+		Name:              store.bucket,
+		ControllerCreated: created,
+	}
+	return nil
+}
+
+// DeleteBootstrapBucket deletes the bucket of the bootstrap data if it was created by the controller, the machines
+// have deleted their bootstrap data by the time the cluster is deleted.
+func (s *PowerVSClusterScope) DeleteBootstrapBucket() error {
+	status := s.IBMPowerVSCluster.Status.BootstrapBucket
+	if status == nil {
+		return nil
+	}
+	if status.ControllerCreated && s.IBMPowerVSCluster.Spec.BootstrapStorage != nil {
+		storage := *s.IBMPowerVSCluster.Spec.BootstrapStorage
+		storage.Bucket = status.Name
+		store, err := newBootstrapDataStore(&storage, s.Cluster.Name)
+		if err != nil {
+			return err
+		}
+		if err := store.DeleteBucket(); err != nil {
+			return errors.Wrapf(err, "failed to delete bootstrap data bucket %s", status.Name)
+		}
+		s.Info("Deleted bootstrap data bucket", "name", status.Name)
+	}
+	s.IBMPowerVSCluster.Status.BootstrapBucket = nil
+	return nil
+}
+
+// getSSHKey returns the key pair of the given name, or nil when it does not exist.
+func (s *PowerVSClusterScope) getSSHKey(name string) (*models.SSHKey, error) {
+	session := s.IBMPowerVSClient.session
+	params := p_cloud_tenants_ssh_keys.NewPcloudTenantsSshkeysGetParamsWithTimeout(TIMEOUT).WithTenantID(session.UserAccount).WithSshkeyName(name)
+	resp, err := session.Power.PCloudTenantsSSHKeys.PcloudTenantsSshkeysGet(params, ibmpisession.NewAuth(session, s.IBMPowerVSCluster.GetServiceInstanceID()))
+	if err != nil {
+		if _, ok := err.(*p_cloud_tenants_ssh_keys.PcloudTenantsSshkeysGetNotFound); ok {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get SSH key %s", name)
+	}
+	return resp.Payload, nil
+}
+
+// getNetworkID returns the id of the network of the cluster recorded in the status, or of the network referenced
+// in the spec when the status was not recorded yet.
+func (s *PowerVSClusterScope) getNetworkID() (string, error) {
+	if network := s.IBMPowerVSCluster.Status.Network; network != nil && network.ID != "" {
+		return network.ID, nil
+	}
+	ref := s.IBMPowerVSCluster.Spec.Network
+	if ref.ID != nil {
+		return *ref.ID, nil
+	}
+	if ref.Name == nil {
+		return "", fmt.Errorf("network of the cluster is not ready")
+	}
+	network, err := s.getNetwork(ref)
+	if err != nil {
+		return "", err
+	}
+	return *network.NetworkID, nil
+}
+
+func (s *PowerVSClusterScope) getNetwork(ref v1alpha4.IBMPowerVSResourceReference) (*models.NetworkReference, error) {
+	if ref.ID == nil && ref.Name == nil {
+		return nil, fmt.Errorf("both ID and Name can't be nil")
+	}
+	networks, err := s.getNetworks()
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks.Networks {
+		if (ref.ID != nil && *network.NetworkID == *ref.ID) || (ref.ID == nil && *network.Name == *ref.Name) {
+			return network, nil
+		}
+	}
+	return nil, fmt.Errorf("network %s not found in service instance %s", resourceReferenceString(ref), s.IBMPowerVSCluster.GetServiceInstanceID())
+}
+
+func (s *PowerVSClusterScope) getNetworks() (*models.Networks, error) {
+	serviceInstanceID := s.IBMPowerVSCluster.GetServiceInstanceID()
+	params := p_cloud_networks.NewPcloudNetworksGetallParamsWithTimeout(TIMEOUT).WithCloudInstanceID(serviceInstanceID)
+	resp, err := s.IBMPowerVSClient.session.Power.PCloudNetworks.PcloudNetworksGetall(params, ibmpisession.NewAuth(s.IBMPowerVSClient.session, serviceInstanceID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list networks")
+	}
+	if resp == nil || resp.Payload == nil {
+		return nil, fmt.Errorf("failed to list networks: empty response")
+	}
+	return resp.Payload, nil
+}
+
+func resourceReferenceString(ref v1alpha4.IBMPowerVSResourceReference) string {
+	if ref.ID != nil {
+		return *ref.ID
+	}
+	return *ref.Name
+}
+
+// networkRange returns the gateway and the first and last usable addresses of an IPv4 CIDR.
+func networkRange(cidr string) (string, string, string, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", "", "", err
+	}
+	base := ip.Mask(ipNet.Mask).To4()
+	if base == nil {
+		return "", "", "", fmt.Errorf("cidr %s is not an IPv4 network", cidr)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones < 2 {
+		return "", "", "", fmt.Errorf("cidr %s is too small", cidr)
+	}
+	first := binary.BigEndian.Uint32(base)
+	last := first | (1<<uint(bits-ones) - 1)
+	address := func(n uint32) string {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, n)
+		return ip.String()
+	}
+	return address(first + 1), address(first + 2), address(last - 1), nil
+}
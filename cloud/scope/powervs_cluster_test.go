@@ -0,0 +1,889 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httptransport "github.com/go-openapi/runtime/client"
+	. "github.com/onsi/gomega"
+
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev1/catalog"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev1/controller"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/controllerv2"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/managementv2"
+	bxmodels "github.com/IBM-Cloud/bluemix-go/models"
+	"github.com/IBM-Cloud/power-go-client/clients/instance"
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/client"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+const fakeServiceInstanceID = "instance"
+
+// fakePowerVSHandler serves a request of the fake PowerVS API with its decoded JSON body,
+// it returns the status code and the object encoded as the response body.
+type fakePowerVSHandler func(r *http.Request, body map[string]interface{}) (int, interface{})
+
+// fakePowerVS is a stand-in of the PowerVS API serving the handlers registered for the method and path of the requests.
+type fakePowerVS struct {
+	mu       sync.Mutex
+	handlers map[string]fakePowerVSHandler
+	requests []string
+}
+
+func newFakePowerVS() *fakePowerVS {
+	return &fakePowerVS{handlers: map[string]fakePowerVSHandler{}}
+}
+
+// handle registers the handler of the requests with the method and path, the path is relative to the service instance.
+func (f *fakePowerVS) handle(method, path string, handler fakePowerVSHandler) {
+	f.handlers[method+" /pcloud/v1/cloud-instances/"+fakeServiceInstanceID+path] = handler
+}
+
+// handleTenant registers the handler of the requests with the method and path, the path is relative to the account.
+func (f *fakePowerVS) handleTenant(method, path string, handler fakePowerVSHandler) {
+	f.handlers[method+" /pcloud/v1/tenants/account"+path] = handler
+}
+
+func (f *fakePowerVS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.Method + " " + r.URL.Path
+	f.requests = append(f.requests, strings.Replace(key, "/pcloud/v1/cloud-instances/"+fakeServiceInstanceID, "", 1))
+	handler, ok := f.handlers[key]
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"description":"not found"}`))
+		return
+	}
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	status, response := handler(r, body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if response != nil {
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+// reply returns a handler replying with the status code and response.
+func reply(status int, response interface{}) fakePowerVSHandler {
+	return func(*http.Request, map[string]interface{}) (int, interface{}) {
+		return status, response
+	}
+}
+
+func newFakePowerVSClient(t *testing.T, powervs *fakePowerVS) *IBMPowerVSClient {
+	server := httptest.NewServer(powervs)
+	t.Cleanup(server.Close)
+
+	session := &ibmpisession.IBMPISession{
+		Power:       client.New(httptransport.New(strings.TrimPrefix(server.URL, "http://"), "/", []string{"http"}), nil),
+		Timeout:     time.Minute,
+		UserAccount: "account",
+		Region:      "us-south",
+		Zone:        "us-south",
+	}
+	return &IBMPowerVSClient{
+		session:                   session,
+		InstanceClient:            instance.NewIBMPIInstanceClient(session, fakeServiceInstanceID),
+		NetworkClient:             instance.NewIBMPINetworkClient(session, fakeServiceInstanceID),
+		ImageClient:               instance.NewIBMPIImageClient(session, fakeServiceInstanceID),
+		DHCPClient:                instance.NewIBMPIDhcpClient(session, fakeServiceInstanceID),
+		JobClient:                 instance.NewIBMPIJobClient(session, fakeServiceInstanceID),
+		VolumeClient:              instance.NewIBMPIVolumeClient(session, fakeServiceInstanceID),
+		PlacementGroupClient:      instance.NewIBMPIPlacementGroupClient(session, fakeServiceInstanceID),
+		SystemPoolClient:          instance.NewIBMPISystemPoolClient(session, fakeServiceInstanceID),
+		StorageClient:             instance.NewIBMPIStorageCapacityClient(session, fakeServiceInstanceID),
+		SharedProcessorPoolClient: NewSharedProcessorPoolClient(session),
+	}
+}
+
+func newPowerVSClusterScope(t *testing.T, powervs *fakePowerVS, spec v1alpha4.IBMPowerVSClusterSpec) *PowerVSClusterScope {
+	spec.ServiceInstanceID = fakeServiceInstanceID
+	return &PowerVSClusterScope{
+		Logger:            klogr.New(),
+		IBMPowerVSClient:  newFakePowerVSClient(t, powervs),
+		Cluster:           &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "capi", UID: "uid"}},
+		IBMPowerVSCluster: &v1alpha4.IBMPowerVSCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "capi"}, Spec: spec},
+	}
+}
+
+func TestValidatePrivateNetwork(t *testing.T) {
+	tests := []struct {
+		name           string
+		privateNetwork v1alpha4.PowerVSPrivateNetwork
+		wantErr        string
+	}{
+		{
+			name:           "static network with a cidr",
+			privateNetwork: v1alpha4.PowerVSPrivateNetwork{CIDR: "192.168.0.0/24", DNSServers: []string{"9.9.9.9"}},
+		},
+		{
+			name:           "static network without cidr",
+			privateNetwork: v1alpha4.PowerVSPrivateNetwork{},
+			wantErr:        "cidr of the private network is required unless dhcp is set",
+		},
+		{
+			name:           "dhcp network",
+			privateNetwork: v1alpha4.PowerVSPrivateNetwork{DHCP: true, Jumbo: true},
+		},
+		{
+			name:           "dhcp network with a cidr and dns servers",
+			privateNetwork: v1alpha4.PowerVSPrivateNetwork{DHCP: true, CIDR: "192.168.0.0/24", DNSServers: []string{"9.9.9.9"}},
+			wantErr:        "cidr, dnsServers of the private network can't be specified with dhcp, they are chosen by the DHCP server",
+		},
+		{
+			name:           "dhcp network with an ip range",
+			privateNetwork: v1alpha4.PowerVSPrivateNetwork{DHCP: true, Gateway: "192.168.0.1", StartIP: "192.168.0.2"},
+			wantErr:        "gateway, startIP and endIP of the private network can't be specified with dhcp, they are chosen by the DHCP server",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := validatePrivateNetwork(&tt.privateNetwork)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcileStaticNetwork(t *testing.T) {
+	network := func(cidr, gateway, start, end string) map[string]interface{} {
+		return map[string]interface{}{
+			"networkID":       "network",
+			"name":            "capi-network",
+			"cidr":            cidr,
+			"gateway":         gateway,
+			"ipAddressRanges": []map[string]string{{"startingIPAddress": start, "endingIPAddress": end}},
+		}
+	}
+	existing := map[string]interface{}{"networks": []map[string]interface{}{
+		{"networkID": "network", "name": "capi-network", "href": "/networks/network", "type": "vlan", "vlanID": 1},
+	}}
+
+	tests := []struct {
+		name       string
+		networks   interface{}
+		network    map[string]interface{}
+		wantErr    bool
+		wantStatus *v1alpha4.PowerVSNetworkStatus
+	}{
+		{
+			name:       "creates the network",
+			networks:   map[string]interface{}{"networks": []interface{}{}},
+			wantStatus: &v1alpha4.PowerVSNetworkStatus{ID: "created", Name: "capi-network", CIDR: "192.168.0.0/24", ControllerCreated: true},
+		},
+		{
+			name:       "uses a matching network of the same name without owning it",
+			networks:   existing,
+			network:    network("192.168.0.0/24", "192.168.0.1", "192.168.0.2", "192.168.0.254"),
+			wantStatus: &v1alpha4.PowerVSNetworkStatus{ID: "network", Name: "capi-network", CIDR: "192.168.0.0/24"},
+		},
+		{
+			name:     "rejects a network of the same name with another cidr",
+			networks: existing,
+			network:  network("10.0.0.0/24", "10.0.0.1", "10.0.0.2", "10.0.0.254"),
+			wantErr:  true,
+		},
+		{
+			name:     "rejects a network of the same name with another ip range",
+			networks: existing,
+			network:  network("192.168.0.0/24", "192.168.0.1", "192.168.0.100", "192.168.0.200"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/networks", reply(http.StatusOK, tt.networks))
+			powervs.handle(http.MethodGet, "/networks/network", reply(http.StatusOK, tt.network))
+			powervs.handle(http.MethodPost, "/networks", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				g.Expect(body).To(HaveKeyWithValue("cidr", "192.168.0.0/24"))
+				g.Expect(body).To(HaveKeyWithValue("gateway", "192.168.0.1"))
+				return http.StatusCreated, map[string]interface{}{"networkID": "created", "name": body["name"], "cidr": body["cidr"]}
+			})
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{
+				PrivateNetwork: &v1alpha4.PowerVSPrivateNetwork{CIDR: "192.168.0.0/24"},
+			})
+
+			ready, err := s.ReconcileNetwork()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(s.IBMPowerVSCluster.Status.Network).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(BeTrue())
+			g.Expect(s.IBMPowerVSCluster.Status.Network).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+// fakeResourceController is an in-memory stand-in of the resource controller holding service instances by GUID,
+// the methods not used by the scope panic.
+type fakeResourceController struct {
+	controller.ResourceServiceInstanceRepository
+	instances map[string]bxmodels.ServiceInstance
+	created   []controller.CreateServiceInstanceRequest
+	deleted   []string
+}
+
+func (f *fakeResourceController) ListInstances(query controller.ServiceInstanceQuery) ([]bxmodels.ServiceInstance, error) {
+	instances := []bxmodels.ServiceInstance{}
+	for _, instance := range f.instances {
+		if instance.Name == query.Name && instance.ResourceGroupID == query.ResourceGroupID && instance.ServiceID == query.ServiceID {
+			instances = append(instances, instance)
+		}
+	}
+	return instances, nil
+}
+
+func (f *fakeResourceController) CreateInstance(request controller.CreateServiceInstanceRequest) (bxmodels.ServiceInstance, error) {
+	f.created = append(f.created, request)
+	instance := bxmodels.ServiceInstance{
+		MetadataType:    &bxmodels.MetadataType{Guid: "created"},
+		Name:            request.Name,
+		ResourceGroupID: request.ResourceGroupID,
+		ServiceID:       "power-iaas-id",
+		State:           serviceInstanceStateProvisioning,
+	}
+	f.instances[instance.Guid] = instance
+	return instance, nil
+}
+
+func (f *fakeResourceController) DeleteInstance(id string, _ bool) error {
+	f.deleted = append(f.deleted, id)
+	delete(f.instances, id)
+	return nil
+}
+
+// resourceServiceInstanceV2 serves the instances of the fake through the v2 API.
+type resourceServiceInstanceV2 struct {
+	controllerv2.ResourceServiceInstanceRepository
+	controller *fakeResourceController
+}
+
+func (f resourceServiceInstanceV2) GetInstance(id string) (bxmodels.ServiceInstanceV2, error) {
+	return bxmodels.ServiceInstanceV2{ServiceInstance: f.controller.instances[id]}, nil
+}
+
+// fakeResourceCatalog serves the Power VS service, its plan and a deployment in the us-south zone.
+type fakeResourceCatalog struct {
+	catalog.ResourceCatalogRepository
+}
+
+func (fakeResourceCatalog) GetServiceID(string) (string, error) {
+	return "power-iaas-id", nil
+}
+
+func (fakeResourceCatalog) Get(id string, _ bool) (bxmodels.Service, error) {
+	return bxmodels.Service{ID: id}, nil
+}
+
+func (fakeResourceCatalog) GetServicePlanID(bxmodels.Service, string) (string, error) {
+	return "plan", nil
+}
+
+func (fakeResourceCatalog) ListDeployments(string) ([]bxmodels.ServiceDeployment, error) {
+	deployment := bxmodels.ServiceDeployment{CatalogCRN: "crn:us-south"}
+	deployment.Metadata.Deployment.Location = "us-south"
+	return []bxmodels.ServiceDeployment{deployment}, nil
+}
+
+func TestReconcileServiceInstance(t *testing.T) {
+	existing := bxmodels.ServiceInstance{
+		MetadataType:    &bxmodels.MetadataType{Guid: "existing"},
+		Name:            "capi-serviceinstance",
+		ResourceGroupID: "group",
+		ServiceID:       "power-iaas-id",
+		State:           serviceInstanceStateActive,
+	}
+
+	tests := []struct {
+		name        string
+		instances   []bxmodels.ServiceInstance
+		wantReady   bool
+		wantStatus  *v1alpha4.PowerVSServiceInstanceStatus
+		wantCreated bool
+		wantDeleted []string
+		wantErr     string
+	}{
+		{
+			name:        "creates the service instance and deletes it with the cluster",
+			wantStatus:  &v1alpha4.PowerVSServiceInstanceStatus{ID: "created", State: serviceInstanceStateProvisioning, ControllerCreated: true},
+			wantCreated: true,
+			wantDeleted: []string{"created"},
+		},
+		{
+			name:      "refuses a service instance of the same name",
+			instances: []bxmodels.ServiceInstance{existing},
+			wantErr:   "service instance capi-serviceinstance already exists in resource group group, set serviceInstanceID to use it",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resourceController := &fakeResourceController{instances: map[string]bxmodels.ServiceInstance{}}
+			for _, instance := range tt.instances {
+				resourceController.instances[instance.Guid] = instance
+			}
+			s := newPowerVSClusterScope(t, newFakePowerVS(), v1alpha4.IBMPowerVSClusterSpec{
+				ServiceInstance: &v1alpha4.PowerVSServiceInstance{
+					Zone:          "us-south",
+					ResourceGroup: v1alpha4.IBMPowerVSResourceReference{ID: pointer.String("group")},
+				},
+			})
+			s.IBMPowerVSCluster.Spec.ServiceInstanceID = ""
+			s.ibmCloudClient = &pkg.Client{
+				ResourceClient:           resourceServiceInstanceV2{controller: resourceController},
+				ResourceControllerClient: resourceController,
+				ResourceCatalogClient:    fakeResourceCatalog{},
+			}
+
+			ready, err := s.ReconcileServiceInstance()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				g.Expect(s.IBMPowerVSCluster.Status.ServiceInstance).To(BeNil())
+				g.Expect(resourceController.created).To(BeEmpty())
+				g.Expect(s.DeleteServiceInstance()).To(Succeed())
+				g.Expect(resourceController.deleted).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(Equal(tt.wantReady))
+			g.Expect(s.IBMPowerVSCluster.Status.ServiceInstance).To(Equal(tt.wantStatus))
+			if tt.wantCreated {
+				g.Expect(resourceController.created).To(ConsistOf(controller.CreateServiceInstanceRequest{
+					Name:            "capi-serviceinstance",
+					ServicePlanID:   "plan",
+					ResourceGroupID: "group",
+					TargetCrn:       "crn:us-south",
+				}))
+			} else {
+				g.Expect(resourceController.created).To(BeEmpty())
+			}
+
+			g.Expect(s.DeleteServiceInstance()).To(Succeed())
+			g.Expect(resourceController.deleted).To(Equal(tt.wantDeleted))
+		})
+	}
+}
+
+// fakeResourceGroups serves the resource groups of an account by name, the methods not used by the scope panic.
+type fakeResourceGroups struct {
+	managementv2.ResourceGroupRepository
+	groups []bxmodels.ResourceGroupv2
+}
+
+func (f fakeResourceGroups) FindByName(query *managementv2.ResourceGroupQuery, name string) ([]bxmodels.ResourceGroupv2, error) {
+	groups := []bxmodels.ResourceGroupv2{}
+	for _, group := range f.groups {
+		if group.AccountID == query.AccountID && group.Name == name {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func TestGetResourceGroupID(t *testing.T) {
+	resourceGroup := func(id, name string) bxmodels.ResourceGroupv2 {
+		return bxmodels.ResourceGroupv2{ResourceGroup: bxmodels.ResourceGroup{ID: id, Name: name, AccountID: "account"}}
+	}
+	groups := fakeResourceGroups{groups: []bxmodels.ResourceGroupv2{
+		resourceGroup("default-id", "default"),
+		resourceGroup("dev-id-1", "dev"),
+		resourceGroup("dev-id-2", "dev"),
+	}}
+
+	tests := []struct {
+		name    string
+		ref     v1alpha4.IBMPowerVSResourceReference
+		want    string
+		wantErr string
+	}{
+		{
+			name: "uses the ID",
+			ref:  v1alpha4.IBMPowerVSResourceReference{ID: pointer.String("group-id")},
+			want: "group-id",
+		},
+		{
+			name: "resolves a unique name",
+			ref:  v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("default")},
+			want: "default-id",
+		},
+		{
+			name:    "rejects an ambiguous name",
+			ref:     v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("dev")},
+			wantErr: "found 2 resources of type resource group with name dev, use the ID to reference it",
+		},
+		{
+			name:    "rejects a missing name",
+			ref:     v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("prod")},
+			wantErr: "failed to find a resource group with name prod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newPowerVSClusterScope(t, newFakePowerVS(), v1alpha4.IBMPowerVSClusterSpec{})
+			s.ibmCloudClient = &pkg.Client{
+				User:                &pkg.User{Account: "account"},
+				ResourceGroupClient: groups,
+			}
+			id, err := s.getResourceGroupID(tt.ref)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(id).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileControlPlaneVIP(t *testing.T) {
+	tests := []struct {
+		name          string
+		network       v1alpha4.IBMPowerVSResourceReference
+		networkStatus *v1alpha4.PowerVSNetworkStatus
+		wantErr       bool
+	}{
+		{
+			name:          "reserves the port on the network of the status",
+			networkStatus: &v1alpha4.PowerVSNetworkStatus{ID: "network", Name: "capi-network"},
+		},
+		{
+			name:    "reserves the port on the network of the spec before the status is recorded",
+			network: v1alpha4.IBMPowerVSResourceReference{ID: pointer.String("network")},
+		},
+		{
+			name:    "looks up the network of the spec by name",
+			network: v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("capi-network")},
+		},
+		{
+			name:    "fails without a network",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/networks", reply(http.StatusOK, map[string]interface{}{"networks": []map[string]interface{}{
+				{"networkID": "network", "name": "capi-network", "href": "/networks/network", "type": "vlan", "vlanID": 1},
+			}}))
+			powervs.handle(http.MethodGet, "/networks/network/ports", reply(http.StatusOK, map[string]interface{}{"ports": []interface{}{}}))
+			powervs.handle(http.MethodPost, "/networks/network/ports", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				g.Expect(body).To(HaveKeyWithValue("ipAddress", "192.168.0.10"))
+				return http.StatusCreated, map[string]interface{}{
+					"portID": "port", "ipAddress": body["ipAddress"], "description": body["description"], "macAddress": "fa:16:3e:00:00:01", "status": "DOWN",
+				}
+			})
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{
+				Network:         tt.network,
+				ControlPlaneVIP: &v1alpha4.PowerVSControlPlaneVIP{IPAddress: "192.168.0.10", Port: 6443},
+			})
+			s.IBMPowerVSCluster.Status.Network = tt.networkStatus
+
+			ready, err := s.ReconcileControlPlaneVIP()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(BeTrue())
+			g.Expect(s.IBMPowerVSCluster.Status.ControlPlaneVIP).To(Equal(&v1alpha4.PowerVSControlPlaneVIPStatus{PortID: "port", IPAddress: "192.168.0.10"}))
+			g.Expect(s.IBMPowerVSCluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: "192.168.0.10", Port: 6443}))
+		})
+	}
+}
+
+func TestReconcilePublicControlPlaneVIP(t *testing.T) {
+	network := func(id, networkType string) map[string]interface{} {
+		return map[string]interface{}{"networkID": id, "name": id, "href": "/networks/" + id, "type": networkType, "vlanID": 1}
+	}
+
+	tests := []struct {
+		name              string
+		publicNetworks    []string
+		publicNetwork     *v1alpha4.IBMPowerVSResourceReference
+		wantPublicNetwork string
+		wantErr           string
+	}{
+		{
+			name:              "reserves the public port on the public network of the service instance",
+			publicNetworks:    []string{"public-1"},
+			wantPublicNetwork: "public-1",
+		},
+		{
+			name:              "reserves the public port on the referenced public network",
+			publicNetworks:    []string{"public-1", "public-2"},
+			publicNetwork:     &v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("public-2")},
+			wantPublicNetwork: "public-2",
+		},
+		{
+			name:           "fails with several public networks",
+			publicNetworks: []string{"public-1", "public-2"},
+			wantErr:        "service instance instance has 2 public networks, set publicNetwork of the control plane virtual IP",
+		},
+		{
+			name:    "fails without a public network",
+			wantErr: "service instance instance has no public network for the control plane virtual IP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			networks := []map[string]interface{}{network("network", "vlan")}
+			for _, id := range tt.publicNetworks {
+				networks = append(networks, network(id, powerVSPublicNetworkType))
+				powervs.handle(http.MethodGet, "/networks/"+id+"/ports", reply(http.StatusOK, map[string]interface{}{"ports": []interface{}{}}))
+				powervs.handle(http.MethodPost, "/networks/"+id+"/ports", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+					g.Expect(body).NotTo(HaveKey("ipAddress"))
+					return http.StatusCreated, map[string]interface{}{
+						"portID": "public-port", "ipAddress": "192.168.151.125", "externalIP": "158.175.162.125",
+						"description": body["description"], "macAddress": "fa:16:3e:00:00:02", "status": "DOWN",
+					}
+				})
+				powervs.handle(http.MethodDelete, "/networks/"+id+"/ports/public-port", reply(http.StatusOK, map[string]interface{}{}))
+			}
+			powervs.handle(http.MethodGet, "/networks", reply(http.StatusOK, map[string]interface{}{"networks": networks}))
+			powervs.handle(http.MethodGet, "/networks/network/ports", reply(http.StatusOK, map[string]interface{}{"ports": []interface{}{}}))
+			powervs.handle(http.MethodPost, "/networks/network/ports", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				return http.StatusCreated, map[string]interface{}{
+					"portID": "port", "ipAddress": body["ipAddress"], "description": body["description"], "macAddress": "fa:16:3e:00:00:01", "status": "DOWN",
+				}
+			})
+			powervs.handle(http.MethodDelete, "/networks/network/ports/port", reply(http.StatusOK, map[string]interface{}{}))
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{
+				ControlPlaneVIP: &v1alpha4.PowerVSControlPlaneVIP{IPAddress: "192.168.0.10", Public: true, PublicNetwork: tt.publicNetwork, Port: 6443},
+			})
+			s.IBMPowerVSCluster.Status.Network = &v1alpha4.PowerVSNetworkStatus{ID: "network", Name: "capi-network"}
+
+			ready, err := s.ReconcileControlPlaneVIP()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				g.Expect(s.IBMPowerVSCluster.Spec.ControlPlaneEndpoint.Host).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(BeTrue())
+			g.Expect(s.IBMPowerVSCluster.Status.ControlPlaneVIP).To(Equal(&v1alpha4.PowerVSControlPlaneVIPStatus{
+				PortID:          "port",
+				IPAddress:       "192.168.0.10",
+				PublicNetworkID: tt.wantPublicNetwork,
+				PublicPortID:    "public-port",
+				ExternalIP:      "158.175.162.125",
+			}))
+			g.Expect(s.IBMPowerVSCluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: "158.175.162.125", Port: 6443}))
+
+			g.Expect(s.DeleteControlPlaneVIP()).To(Succeed())
+			g.Expect(s.IBMPowerVSCluster.Status.ControlPlaneVIP).To(BeNil())
+			g.Expect(powervs.requests).To(ContainElements(
+				"DELETE /networks/"+tt.wantPublicNetwork+"/ports/public-port",
+				"DELETE /networks/network/ports/port",
+			))
+		})
+	}
+}
+
+func TestReconcilePlacementGroups(t *testing.T) {
+	group := func(id, name string, members ...string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name, "policy": "anti-affinity", "members": members}
+	}
+
+	tests := []struct {
+		name         string
+		specs        []v1alpha4.PowerVSPlacementGroup
+		statuses     []v1alpha4.PowerVSPlacementGroupStatus
+		groups       []map[string]interface{}
+		wantStatuses []v1alpha4.PowerVSPlacementGroupStatus
+		wantCreated  []string
+		wantDeleted  []string
+	}{
+		{
+			name:         "creates the missing groups",
+			specs:        []v1alpha4.PowerVSPlacementGroup{{Name: "control-plane", Policy: "anti-affinity"}},
+			wantStatuses: []v1alpha4.PowerVSPlacementGroupStatus{{Name: "control-plane", ID: "created", ControllerCreated: true}},
+			wantCreated:  []string{"control-plane"},
+		},
+		{
+			name:         "uses a group of the same name without owning it",
+			specs:        []v1alpha4.PowerVSPlacementGroup{{Name: "control-plane", Policy: "anti-affinity"}},
+			groups:       []map[string]interface{}{group("existing", "control-plane")},
+			wantStatuses: []v1alpha4.PowerVSPlacementGroupStatus{{Name: "control-plane", ID: "existing"}},
+		},
+		{
+			name:         "keeps the recorded groups",
+			specs:        []v1alpha4.PowerVSPlacementGroup{{Name: "control-plane", Policy: "anti-affinity"}},
+			statuses:     []v1alpha4.PowerVSPlacementGroupStatus{{Name: "control-plane", ID: "recorded", ControllerCreated: true}},
+			wantStatuses: []v1alpha4.PowerVSPlacementGroupStatus{{Name: "control-plane", ID: "recorded", ControllerCreated: true}},
+		},
+		{
+			name: "deletes the groups removed from the spec once they have no members",
+			statuses: []v1alpha4.PowerVSPlacementGroupStatus{
+				{Name: "empty", ID: "empty", ControllerCreated: true},
+				{Name: "used", ID: "used", ControllerCreated: true},
+				{Name: "existing", ID: "existing"},
+			},
+			groups:       []map[string]interface{}{group("empty", "empty"), group("used", "used", "instance"), group("existing", "existing")},
+			wantStatuses: []v1alpha4.PowerVSPlacementGroupStatus{{Name: "used", ID: "used", ControllerCreated: true}},
+			wantDeleted:  []string{"empty"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var created, deleted []string
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/placement-groups", reply(http.StatusOK, map[string]interface{}{"placementGroups": tt.groups}))
+			for _, group := range tt.groups {
+				id := group["id"].(string)
+				powervs.handle(http.MethodGet, "/placement-groups/"+id, reply(http.StatusOK, group))
+				powervs.handle(http.MethodDelete, "/placement-groups/"+id, func(*http.Request, map[string]interface{}) (int, interface{}) {
+					deleted = append(deleted, id)
+					return http.StatusOK, map[string]interface{}{}
+				})
+			}
+			powervs.handle(http.MethodPost, "/placement-groups", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				created = append(created, body["name"].(string))
+				return http.StatusOK, group("created", body["name"].(string))
+			})
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{PlacementGroups: tt.specs})
+			s.IBMPowerVSCluster.Status.PlacementGroups = tt.statuses
+
+			g.Expect(s.ReconcilePlacementGroups()).To(Succeed())
+			g.Expect(s.IBMPowerVSCluster.Status.PlacementGroups).To(Equal(tt.wantStatuses))
+			g.Expect(created).To(Equal(tt.wantCreated))
+			g.Expect(deleted).To(Equal(tt.wantDeleted))
+		})
+	}
+}
+
+func TestReconcileSSHKey(t *testing.T) {
+	const name = "default-capi-sshkey"
+
+	tests := []struct {
+		name        string
+		existing    string
+		status      *v1alpha4.PowerVSSSHKeyStatus
+		wantErr     bool
+		wantStatus  *v1alpha4.PowerVSSSHKeyStatus
+		wantRequest string
+		wantDeleted bool
+	}{
+		{
+			name:        "creates the key pair and deletes it with the cluster",
+			wantStatus:  &v1alpha4.PowerVSSSHKeyStatus{Name: name, ControllerCreated: true},
+			wantRequest: http.MethodPost,
+			wantDeleted: true,
+		},
+		{
+			name:        "updates the key pair created by the controller",
+			existing:    "ssh-rsa old",
+			status:      &v1alpha4.PowerVSSSHKeyStatus{Name: name, ControllerCreated: true},
+			wantStatus:  &v1alpha4.PowerVSSSHKeyStatus{Name: name, ControllerCreated: true},
+			wantRequest: http.MethodPut,
+			wantDeleted: true,
+		},
+		{
+			name:       "uses a key pair of the same name holding the key without owning it",
+			existing:   "ssh-rsa key",
+			wantStatus: &v1alpha4.PowerVSSSHKeyStatus{Name: name},
+		},
+		{
+			name:     "does not overwrite a key pair of the same name holding another key",
+			existing: "ssh-rsa other",
+			wantErr:  true,
+		},
+		{
+			name:     "does not overwrite a key pair used without owning it",
+			existing: "ssh-rsa other",
+			status:   &v1alpha4.PowerVSSSHKeyStatus{Name: name},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			existing := tt.existing
+			var request string
+			deleted := false
+			powervs := newFakePowerVS()
+			powervs.handleTenant(http.MethodGet, "/sshkeys/"+name, func(*http.Request, map[string]interface{}) (int, interface{}) {
+				if existing == "" {
+					return http.StatusNotFound, map[string]string{"description": "not found"}
+				}
+				return http.StatusOK, map[string]string{"name": name, "sshKey": existing}
+			})
+			powervs.handleTenant(http.MethodPost, "/sshkeys", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				request = http.MethodPost
+				existing = body["sshKey"].(string)
+				return http.StatusCreated, body
+			})
+			powervs.handleTenant(http.MethodPut, "/sshkeys/"+name, func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				request = http.MethodPut
+				existing = body["sshKey"].(string)
+				return http.StatusOK, body
+			})
+			powervs.handleTenant(http.MethodDelete, "/sshkeys/"+name, func(*http.Request, map[string]interface{}) (int, interface{}) {
+				deleted = true
+				existing = ""
+				return http.StatusOK, map[string]interface{}{}
+			})
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{
+				SSHKeySecret: &v1alpha4.SSHKeySecretReference{Name: "ssh-key"},
+			})
+			s.client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ssh-key"},
+				Data:       map[string][]byte{"ssh-publickey": []byte("ssh-rsa key\n")},
+			}).Build()
+			s.IBMPowerVSCluster.Status.SSHKey = tt.status
+
+			err := s.ReconcileSSHKey()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(existing).To(Equal(tt.existing))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.IBMPowerVSCluster.Status.SSHKey).To(Equal(tt.wantStatus))
+			g.Expect(request).To(Equal(tt.wantRequest))
+			g.Expect(existing).To(Equal("ssh-rsa key"))
+
+			g.Expect(s.DeleteSSHKey()).To(Succeed())
+			g.Expect(deleted).To(Equal(tt.wantDeleted))
+			g.Expect(s.IBMPowerVSCluster.Status.SSHKey).To(BeNil())
+		})
+	}
+}
+
+func TestReconcileSharedProcessorPool(t *testing.T) {
+	pool := func(id string, reservedCores int64) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": "capi-pool", "hostGroup": "s922", "reservedCores": reservedCores, "status": "active"}
+	}
+
+	tests := []struct {
+		name        string
+		pools       []map[string]interface{}
+		status      *v1alpha4.PowerVSSharedProcessorPoolStatus
+		servers     []map[string]string
+		wantStatus  *v1alpha4.PowerVSSharedProcessorPoolStatus
+		wantRequest []string
+		wantDeleted bool
+	}{
+		{
+			name:        "creates the pool and deletes it with the cluster",
+			wantStatus:  &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "created", ReservedCores: 2, State: "active", ControllerCreated: true},
+			wantRequest: []string{"POST /shared-processor-pools"},
+			wantDeleted: true,
+		},
+		{
+			name:       "uses a pool of the same name without resizing or deleting it",
+			pools:      []map[string]interface{}{pool("existing", 1)},
+			wantStatus: &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "existing", ReservedCores: 1, State: "active"},
+		},
+		{
+			name:        "resizes the pool created by the controller",
+			pools:       []map[string]interface{}{pool("existing", 1)},
+			status:      &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "existing", ControllerCreated: true},
+			wantStatus:  &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "existing", ReservedCores: 2, State: "active", ControllerCreated: true},
+			wantRequest: []string{"PUT /shared-processor-pools/existing"},
+			wantDeleted: true,
+		},
+		{
+			name:       "keeps the pool while it has instances",
+			pools:      []map[string]interface{}{pool("existing", 2)},
+			status:     &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "existing", ControllerCreated: true},
+			servers:    []map[string]string{{"id": "instance", "name": "machine"}},
+			wantStatus: &v1alpha4.PowerVSSharedProcessorPoolStatus{Name: "capi-pool", ID: "existing", ReservedCores: 2, State: "active", ControllerCreated: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var requests []string
+			deleted := false
+			pools := map[string]map[string]interface{}{}
+			for _, p := range tt.pools {
+				pools[p["id"].(string)] = p
+			}
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/shared-processor-pools", reply(http.StatusOK, map[string]interface{}{"sharedProcessorPools": tt.pools}))
+			powervs.handle(http.MethodPost, "/shared-processor-pools", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				requests = append(requests, "POST /shared-processor-pools")
+				g.Expect(body).To(Equal(map[string]interface{}{"name": "capi-pool", "hostGroup": "s922", "reservedCores": float64(2)}))
+				pools["created"] = pool("created", 2)
+				return http.StatusCreated, pools["created"]
+			})
+			for _, id := range []string{"created", "existing"} {
+				id := id
+				powervs.handle(http.MethodGet, "/shared-processor-pools/"+id, func(*http.Request, map[string]interface{}) (int, interface{}) {
+					return http.StatusOK, map[string]interface{}{"sharedProcessorPool": pools[id], "servers": tt.servers}
+				})
+				powervs.handle(http.MethodPut, "/shared-processor-pools/"+id, func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+					requests = append(requests, "PUT /shared-processor-pools/"+id)
+					pools[id]["reservedCores"] = body["reservedCores"]
+					return http.StatusOK, pools[id]
+				})
+				powervs.handle(http.MethodDelete, "/shared-processor-pools/"+id, func(*http.Request, map[string]interface{}) (int, interface{}) {
+					deleted = true
+					return http.StatusOK, nil
+				})
+			}
+			s := newPowerVSClusterScope(t, powervs, v1alpha4.IBMPowerVSClusterSpec{
+				SharedProcessorPool: &v1alpha4.PowerVSSharedProcessorPool{Name: "capi-pool", HostGroup: "s922", ReservedCores: 2},
+			})
+			s.IBMPowerVSCluster.Status.SharedProcessorPool = tt.status
+
+			g.Expect(s.ReconcileSharedProcessorPool()).To(Succeed())
+			g.Expect(s.IBMPowerVSCluster.Status.SharedProcessorPool).To(Equal(tt.wantStatus))
+			g.Expect(requests).To(Equal(tt.wantRequest))
+
+			done, err := s.DeleteSharedProcessorPool()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(done).To(Equal(len(tt.servers) == 0))
+			g.Expect(deleted).To(Equal(tt.wantDeleted))
+		})
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+const (
+	imageStateActive = "active"
+	imageStateFailed = "failed"
+
+	jobStateCompleted = "completed"
+	jobStateFailed    = "failed"
+)
+
+// PowerVSImageScopeParams defines the input parameters used to create a new PowerVSImageScope.
+type PowerVSImageScopeParams struct {
+	Client            client.Client
+	Logger            logr.Logger
+	IBMPowerVSImage   *v1alpha4.IBMPowerVSImage
+	ServiceInstanceID string
+}
+
+// PowerVSImageScope defines a scope defined around a Power VS Image.
+type PowerVSImageScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+
+	IBMPowerVSClient  *IBMPowerVSClient
+	IBMPowerVSImage   *v1alpha4.IBMPowerVSImage
+	ServiceInstanceID string
+}
+
+// NewPowerVSImageScope creates a new PowerVSImageScope from the supplied parameters.
+func NewPowerVSImageScope(params PowerVSImageScopeParams) (*PowerVSImageScope, error) {
+	if params.IBMPowerVSImage == nil {
+		return nil, errors.New("failed to generate new scope from nil IBMPowerVSImage")
+	}
+	if params.ServiceInstanceID == "" {
+		return nil, errors.New("failed to generate new scope without service instance")
+	}
+
+	if params.Logger == nil {
+		params.Logger = klogr.New()
+	}
+
+	ibmCloudClient, err := pkg.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud client")
+	}
+	c, err := newIBMPowerVSClientForServiceInstance(ibmCloudClient, params.ServiceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	helper, err := patch.NewHelper(params.IBMPowerVSImage, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	return &PowerVSImageScope{
+		Logger:            params.Logger,
+		client:            params.Client,
+		patchHelper:       helper,
+		IBMPowerVSClient:  c,
+		IBMPowerVSImage:   params.IBMPowerVSImage,
+		ServiceInstanceID: params.ServiceInstanceID,
+	}, nil
+}
+
+// PatchObject persists the image configuration and status.
+func (s *PowerVSImageScope) PatchObject() error {
+	return s.patchHelper.Patch(context.TODO(), s.IBMPowerVSImage)
+}
+
+// Close closes the current scope persisting the image configuration and status.
+func (s *PowerVSImageScope) Close() error {
+	return s.PatchObject()
+}
+
+// ReconcileImage imports the image from Cloud Object Storage and tracks the import job until the image is active.
+// It returns whether the image is done, either active or failed to import. An existing image of the same name is
+// not used since it can't be told apart from one imported outside the controller.
+func (s *PowerVSImageScope) ReconcileImage() (bool, error) {
+	image := s.IBMPowerVSImage
+	if image.Status.ImageID == "" {
+		if image.Status.JobID != "" {
+			return s.reconcileImportJob()
+		}
+		existing, err := s.getImageByName(image.Name)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil {
+			conditions.MarkFalse(image, v1alpha4.ImageReadyCondition, v1alpha4.ImageNameInUseReason, clusterv1.ConditionSeverityError,
+				"image %s already exists in service instance %s", *existing.ImageID, s.ServiceInstanceID)
+			return false, fmt.Errorf("image %s of the same name already exists in service instance %s", *existing.ImageID, s.ServiceInstanceID)
+		}
+		return false, s.importImage()
+	}
+
+	img, err := s.IBMPowerVSClient.ImageClient.Get(image.Status.ImageID, s.ServiceInstanceID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get image %s", image.Status.ImageID)
+	}
+	image.Status.ImageState = img.State
+	switch img.State {
+	case imageStateActive:
+		image.Status.Ready = true
+		conditions.MarkTrue(image, v1alpha4.ImageReadyCondition)
+		return true, nil
+	case imageStateFailed:
+		image.Status.Ready = false
+		conditions.MarkFalse(image, v1alpha4.ImageReadyCondition, v1alpha4.ImageNotActiveReason, clusterv1.ConditionSeverityError, "image is in %s state", img.State)
+		return true, nil
+	default:
+		image.Status.Ready = false
+		conditions.MarkFalse(image, v1alpha4.ImageReadyCondition, v1alpha4.ImageNotActiveReason, clusterv1.ConditionSeverityInfo, "image is in %s state", img.State)
+		return false, nil
+	}
+}
+
+// importImage starts the job importing the image from Cloud Object Storage.
+func (s *PowerVSImageScope) importImage() error {
+	spec := s.IBMPowerVSImage.Spec
+	body := &models.CreateCosImageImportJob{
+		ImageName:     &s.IBMPowerVSImage.Name,
+		BucketName:    &spec.Bucket,
+		ImageFilename: &spec.Object,
+		Region:        &spec.Region,
+		BucketAccess:  &spec.BucketAccess,
+		StorageType:   spec.StorageType,
+		OsType:        spec.OSType,
+	}
+	if spec.BucketAccess == models.CreateCosImageImportJobBucketAccessPrivate {
+		accessKeyID, secretAccessKey, err := cosCredentials()
+		if err != nil {
+			return errors.Wrap(err, "failed to get credentials of the private bucket")
+		}
+		body.AccessKey = accessKeyID
+		body.SecretKey = secretAccessKey
+	}
+
+	job, err := s.IBMPowerVSClient.ImageClient.CreateCosImage(body, s.ServiceInstanceID)
+	if err != nil {
+		return errors.Wrap(err, "failed to import image")
+	}
+	s.Info("Started image import job", "id", *job.ID)
+	s.IBMPowerVSImage.Status.JobID = *job.ID
+	s.IBMPowerVSImage.Status.ControllerCreated = true
+	conditions.MarkFalse(s.IBMPowerVSImage, v1alpha4.ImageReadyCondition, v1alpha4.ImageImportingReason, clusterv1.ConditionSeverityInfo,
+		"import job %s started", *job.ID)
+	return nil
+}
+
+// reconcileImportJob records the state of the import job and the image it created once the job completes.
+func (s *PowerVSImageScope) reconcileImportJob() (bool, error) {
+	image := s.IBMPowerVSImage
+	job, err := s.IBMPowerVSClient.JobClient.Get(image.Status.JobID, s.ServiceInstanceID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get image import job %s", image.Status.JobID)
+	}
+	if job.Status == nil || job.Status.State == nil {
+		return false, nil
+	}
+	switch *job.Status.State {
+	case jobStateFailed:
+		conditions.MarkFalse(image, v1alpha4.ImageReadyCondition, v1alpha4.ImageImportFailedReason, clusterv1.ConditionSeverityError, "%s", job.Status.Message)
+		return true, nil
+	case jobStateCompleted:
+		imported, err := s.getImageByName(image.Name)
+		if err != nil {
+			return false, err
+		}
+		if imported == nil {
+			return false, fmt.Errorf("image %s not found after import job %s completed", image.Name, image.Status.JobID)
+		}
+		image.Status.ImageID = *imported.ImageID
+		s.Info("Imported image", "id", image.Status.ImageID)
+		// The state of the image is recorded on the next reconciliation.
+		return false, nil
+	default:
+		conditions.MarkFalse(image, v1alpha4.ImageReadyCondition, v1alpha4.ImageImportingReason, clusterv1.ConditionSeverityInfo, "import job is in %s state", *job.Status.State)
+		return false, nil
+	}
+}
+
+// DeleteImage deletes the image if it was imported by the controller, waiting for the import job to finish
+// when the image is still being imported.
+func (s *PowerVSImageScope) DeleteImage() error {
+	status := &s.IBMPowerVSImage.Status
+	if !status.ControllerCreated {
+		return nil
+	}
+	if status.ImageID == "" && status.JobID != "" {
+		done, err := s.reconcileImportJob()
+		if err != nil {
+			return err
+		}
+		if status.ImageID == "" && !done {
+			return fmt.Errorf("image import job %s is still running", status.JobID)
+		}
+	}
+	imageID := status.ImageID
+	if imageID == "" {
+		return nil
+	}
+	if err := s.IBMPowerVSClient.ImageClient.Delete(imageID, s.ServiceInstanceID); err != nil {
+		return errors.Wrapf(err, "failed to delete image %s", imageID)
+	}
+	s.Info("Deleted image", "id", imageID)
+	return nil
+}
+
+func (s *PowerVSImageScope) getImageByName(name string) (*models.ImageReference, error) {
+	images, err := s.IBMPowerVSClient.ImageClient.GetAll(s.ServiceInstanceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images")
+	}
+	for _, image := range images.Images {
+		if *image.Name == name {
+			return image, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func newPowerVSImageScope(t *testing.T, powervs *fakePowerVS, status v1alpha4.IBMPowerVSImageStatus) *PowerVSImageScope {
+	return &PowerVSImageScope{
+		Logger:           klogr.New(),
+		IBMPowerVSClient: newFakePowerVSClient(t, powervs),
+		IBMPowerVSImage: &v1alpha4.IBMPowerVSImage{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "capi-image"},
+			Spec: v1alpha4.IBMPowerVSImageSpec{
+				Bucket:       "bucket",
+				Object:       "capi-image.ova.gz",
+				Region:       "us-south",
+				BucketAccess: "public",
+			},
+			Status: status,
+		},
+		ServiceInstanceID: fakeServiceInstanceID,
+	}
+}
+
+func TestReconcileImage(t *testing.T) {
+	images := func(names ...string) map[string]interface{} {
+		references := []map[string]string{}
+		for _, name := range names {
+			references = append(references, map[string]string{"imageID": name + "-id", "name": name})
+		}
+		return map[string]interface{}{"images": references}
+	}
+
+	tests := []struct {
+		name       string
+		status     v1alpha4.IBMPowerVSImageStatus
+		images     map[string]interface{}
+		jobState   string
+		imageState string
+		wantDone   bool
+		wantErr    bool
+		wantStatus v1alpha4.IBMPowerVSImageStatus
+		wantReason string
+	}{
+		{
+			name:       "starts the import job",
+			images:     images("other"),
+			wantStatus: v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			wantReason: v1alpha4.ImageImportingReason,
+		},
+		{
+			name:       "does not use an image of the same name",
+			images:     images("capi-image"),
+			wantErr:    true,
+			wantReason: v1alpha4.ImageNameInUseReason,
+		},
+		{
+			name:       "waits for the import job",
+			status:     v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			jobState:   "running",
+			wantStatus: v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			wantReason: v1alpha4.ImageImportingReason,
+		},
+		{
+			name:       "reports the failure of the import job",
+			status:     v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			jobState:   jobStateFailed,
+			wantDone:   true,
+			wantStatus: v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			wantReason: v1alpha4.ImageImportFailedReason,
+		},
+		{
+			name:       "records the image of the completed import job",
+			status:     v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			images:     images("capi-image"),
+			jobState:   jobStateCompleted,
+			wantStatus: v1alpha4.IBMPowerVSImageStatus{JobID: "job", ImageID: "capi-image-id", ControllerCreated: true},
+		},
+		{
+			name:       "reports the active image",
+			status:     v1alpha4.IBMPowerVSImageStatus{JobID: "job", ImageID: "capi-image-id", ControllerCreated: true},
+			imageState: imageStateActive,
+			wantDone:   true,
+			wantStatus: v1alpha4.IBMPowerVSImageStatus{
+				Ready: true, JobID: "job", ImageID: "capi-image-id", ImageState: imageStateActive, ControllerCreated: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/images", reply(http.StatusOK, tt.images))
+			powervs.handle(http.MethodGet, "/images/capi-image-id", reply(http.StatusOK, map[string]interface{}{
+				"imageID": "capi-image-id", "name": "capi-image", "state": tt.imageState,
+			}))
+			powervs.handle(http.MethodGet, "/jobs/job", reply(http.StatusOK, map[string]interface{}{
+				"id": "job", "status": map[string]interface{}{"state": tt.jobState, "message": "bucket not found"},
+			}))
+			powervs.handle(http.MethodPost, "/cos-images", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				g.Expect(body).To(HaveKeyWithValue("imageName", "capi-image"))
+				g.Expect(body).To(HaveKeyWithValue("bucketName", "bucket"))
+				return http.StatusAccepted, map[string]string{"id": "job"}
+			})
+			s := newPowerVSImageScope(t, powervs, tt.status)
+
+			done, err := s.ReconcileImage()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(done).To(Equal(tt.wantDone))
+			status := s.IBMPowerVSImage.Status
+			status.Conditions = nil
+			g.Expect(status).To(Equal(tt.wantStatus))
+			g.Expect(conditions.GetReason(s.IBMPowerVSImage, v1alpha4.ImageReadyCondition)).To(Equal(tt.wantReason))
+		})
+	}
+}
+
+func TestDeleteImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      v1alpha4.IBMPowerVSImageStatus
+		jobState    string
+		wantErr     bool
+		wantDeleted bool
+	}{
+		{
+			name:        "deletes the imported image",
+			status:      v1alpha4.IBMPowerVSImageStatus{JobID: "job", ImageID: "capi-image-id", ControllerCreated: true},
+			wantDeleted: true,
+		},
+		{
+			name:   "keeps an image not imported by the controller",
+			status: v1alpha4.IBMPowerVSImageStatus{ImageID: "capi-image-id"},
+		},
+		{
+			name:   "does nothing without an image",
+			status: v1alpha4.IBMPowerVSImageStatus{},
+		},
+		{
+			name:     "waits for the running import job",
+			status:   v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			jobState: "running",
+			wantErr:  true,
+		},
+		{
+			name:        "deletes the image of the completed import job",
+			status:      v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			jobState:    jobStateCompleted,
+			wantDeleted: true,
+		},
+		{
+			name:     "does nothing when the import job failed",
+			status:   v1alpha4.IBMPowerVSImageStatus{JobID: "job", ControllerCreated: true},
+			jobState: jobStateFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			deleted := false
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/images", reply(http.StatusOK, map[string]interface{}{
+				"images": []map[string]string{{"imageID": "capi-image-id", "name": "capi-image"}},
+			}))
+			powervs.handle(http.MethodGet, "/jobs/job", reply(http.StatusOK, map[string]interface{}{
+				"id": "job", "status": map[string]interface{}{"state": tt.jobState},
+			}))
+			powervs.handle(http.MethodDelete, "/images/capi-image-id", func(*http.Request, map[string]interface{}) (int, interface{}) {
+				deleted = true
+				return http.StatusOK, map[string]interface{}{}
+			})
+			s := newPowerVSImageScope(t, powervs, tt.status)
+
+			err := s.DeleteImage()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(deleted).To(Equal(tt.wantDeleted))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+// GetIPPool returns the IBMPowerVSIPPool of the given name in the namespace of the machine. The pool is read from
+// the API server rather than the cache, so that the claims of the other machines are not missed.
+func (m *PowerVSMachineScope) GetIPPool(name string) (*v1alpha4.IBMPowerVSIPPool, error) {
+	pool := &v1alpha4.IBMPowerVSIPPool{}
+	key := types.NamespacedName{Namespace: m.IBMPowerVSMachine.Namespace, Name: name}
+	if err := m.apiReader.Get(context.TODO(), key, pool); err != nil {
+		return nil, errors.Wrapf(err, "failed to get IBMPowerVSIPPool %s", key)
+	}
+	return pool, nil
+}
+
+// ClaimAddress returns the address of the IBMPowerVSIPPool claimed by the machine for the network, claiming a free
+// address first, and records the claim in the machine status. The claim is recorded in the pool status with an
+// update conditioned on the version of the pool which was read, so that concurrent claims of the same address
+// conflict and are retried.
+func (m *PowerVSMachineScope) ClaimAddress(name, networkID string) (string, error) {
+	machine := m.IBMPowerVSMachine.Name
+	var address string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := m.GetIPPool(name)
+		if err != nil {
+			return err
+		}
+		used := map[string]bool{}
+		for _, allocation := range pool.Status.Allocations {
+			if allocation.Machine == machine && allocation.Network == networkID {
+				address = allocation.Address
+				return nil
+			}
+			used[allocation.Address] = true
+		}
+
+		address, err = freeAddress(pool.Spec.Ranges, used)
+		if err != nil {
+			return errors.Wrapf(err, "failed to claim an address from IBMPowerVSIPPool %s", name)
+		}
+		pool.Status.Allocations = append(pool.Status.Allocations, v1alpha4.IPAllocation{Address: address, Machine: machine, Network: networkID})
+		if err := m.client.Status().Update(context.TODO(), pool); err != nil {
+			return err
+		}
+		m.Info("Claimed address", "pool", name, "network", networkID, "address", address)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claim := v1alpha4.PowerVSIPClaim{Pool: name, Network: networkID, Address: address}
+	for _, recorded := range m.IBMPowerVSMachine.Status.IPClaims {
+		if recorded == claim {
+			return address, nil
+		}
+	}
+	m.IBMPowerVSMachine.Status.IPClaims = append(m.IBMPowerVSMachine.Status.IPClaims, claim)
+	return address, nil
+}
+
+// ReleaseAddresses releases the addresses claimed by the machine recorded in its status, each claim is removed
+// from its IBMPowerVSIPPool and then from the status.
+func (m *PowerVSMachineScope) ReleaseAddresses() error {
+	for len(m.IBMPowerVSMachine.Status.IPClaims) > 0 {
+		claim := m.IBMPowerVSMachine.Status.IPClaims[0]
+		if err := m.releaseAddress(claim); err != nil {
+			return err
+		}
+		m.IBMPowerVSMachine.Status.IPClaims = m.IBMPowerVSMachine.Status.IPClaims[1:]
+	}
+	return nil
+}
+
+// releaseAddress removes the allocation of the claim from its IBMPowerVSIPPool, a deleted pool has nothing to release.
+func (m *PowerVSMachineScope) releaseAddress(claim v1alpha4.PowerVSIPClaim) error {
+	machine := m.IBMPowerVSMachine.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := m.GetIPPool(claim.Pool)
+		if err != nil {
+			return err
+		}
+		allocations := make([]v1alpha4.IPAllocation, 0, len(pool.Status.Allocations))
+		for _, allocation := range pool.Status.Allocations {
+			if allocation.Machine == machine && allocation.Network == claim.Network && allocation.Address == claim.Address {
+				continue
+			}
+			allocations = append(allocations, allocation)
+		}
+		if len(allocations) == len(pool.Status.Allocations) {
+			return nil
+		}
+		pool.Status.Allocations = allocations
+		if err := m.client.Status().Update(context.TODO(), pool); err != nil {
+			return err
+		}
+		m.Info("Released address", "pool", claim.Pool, "network", claim.Network, "address", claim.Address)
+		return nil
+	})
+	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+		return err
+	}
+	return nil
+}
+
+// freeAddress returns the first address of the ranges which is not used.
+func freeAddress(ranges []v1alpha4.IPRange, used map[string]bool) (string, error) {
+	for _, r := range ranges {
+		start, err := parseIPv4(r.Start)
+		if err != nil {
+			return "", err
+		}
+		end, err := parseIPv4(r.End)
+		if err != nil {
+			return "", err
+		}
+		for i := start; i <= end && i >= start; i++ {
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, i)
+			if !used[ip.String()] {
+				return ip.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no free address")
+}
+
+func parseIPv4(address string) (uint32, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("invalid IPv4 address %q", address)
+	}
+	return binary.BigEndian.Uint32(ip), nil
+}
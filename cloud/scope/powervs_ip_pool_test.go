@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func newIPPoolClient(t *testing.T, pool *v1alpha4.IBMPowerVSIPPool) client.Client {
+	scheme := runtime.NewScheme()
+	if err := v1alpha4.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()
+}
+
+func newIPPool(allocations ...v1alpha4.IPAllocation) *v1alpha4.IBMPowerVSIPPool {
+	return &v1alpha4.IBMPowerVSIPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pool"},
+		Spec: v1alpha4.IBMPowerVSIPPoolSpec{
+			Network: v1alpha4.IBMPowerVSResourceReference{Name: pointer.String("network")},
+			Ranges:  []v1alpha4.IPRange{{Start: "192.168.0.10", End: "192.168.0.11"}, {Start: "192.168.0.20", End: "192.168.0.22"}},
+		},
+		Status: v1alpha4.IBMPowerVSIPPoolStatus{Allocations: allocations},
+	}
+}
+
+func newIPPoolMachineScope(c client.Client, name string) *PowerVSMachineScope {
+	return &PowerVSMachineScope{
+		Logger:            klogr.New(),
+		client:            c,
+		apiReader:         c,
+		IBMPowerVSMachine: &v1alpha4.IBMPowerVSMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}},
+	}
+}
+
+func getIPPoolAllocations(g *WithT, c client.Client) []v1alpha4.IPAllocation {
+	pool := &v1alpha4.IBMPowerVSIPPool{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "pool"}, pool)).To(Succeed())
+	return pool.Status.Allocations
+}
+
+func TestClaimAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		allocations     []v1alpha4.IPAllocation
+		network         string
+		wantAddress     string
+		wantErr         bool
+		wantAllocations []v1alpha4.IPAllocation
+	}{
+		{
+			name:            "claims the first free address",
+			allocations:     []v1alpha4.IPAllocation{{Address: "192.168.0.10", Machine: "other", Network: "network"}},
+			network:         "network",
+			wantAddress:     "192.168.0.11",
+			wantAllocations: []v1alpha4.IPAllocation{{Address: "192.168.0.10", Machine: "other", Network: "network"}, {Address: "192.168.0.11", Machine: "machine", Network: "network"}},
+		},
+		{
+			name:            "returns the address already claimed for the network",
+			allocations:     []v1alpha4.IPAllocation{{Address: "192.168.0.21", Machine: "machine", Network: "network"}},
+			network:         "network",
+			wantAddress:     "192.168.0.21",
+			wantAllocations: []v1alpha4.IPAllocation{{Address: "192.168.0.21", Machine: "machine", Network: "network"}},
+		},
+		{
+			name:        "claims another address for another network",
+			allocations: []v1alpha4.IPAllocation{{Address: "192.168.0.10", Machine: "machine", Network: "network"}},
+			network:     "other-network",
+			wantAddress: "192.168.0.11",
+			wantAllocations: []v1alpha4.IPAllocation{
+				{Address: "192.168.0.10", Machine: "machine", Network: "network"}, {Address: "192.168.0.11", Machine: "machine", Network: "other-network"},
+			},
+		},
+		{
+			name:        "continues with the next range",
+			allocations: []v1alpha4.IPAllocation{{Address: "192.168.0.10", Machine: "a", Network: "network"}, {Address: "192.168.0.11", Machine: "b", Network: "network"}},
+			network:     "network",
+			wantAddress: "192.168.0.20",
+			wantAllocations: []v1alpha4.IPAllocation{
+				{Address: "192.168.0.10", Machine: "a", Network: "network"}, {Address: "192.168.0.11", Machine: "b", Network: "network"},
+				{Address: "192.168.0.20", Machine: "machine", Network: "network"},
+			},
+		},
+		{
+			name: "fails once the ranges are exhausted",
+			allocations: []v1alpha4.IPAllocation{
+				{Address: "192.168.0.10", Machine: "a", Network: "network"}, {Address: "192.168.0.11", Machine: "b", Network: "network"},
+				{Address: "192.168.0.20", Machine: "c", Network: "network"}, {Address: "192.168.0.21", Machine: "d", Network: "network"},
+				{Address: "192.168.0.22", Machine: "e", Network: "network"},
+			},
+			network: "network",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := newIPPoolClient(t, newIPPool(tt.allocations...))
+			s := newIPPoolMachineScope(c, "machine")
+
+			address, err := s.ClaimAddress("pool", tt.network)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(s.IBMPowerVSMachine.Status.IPClaims).To(BeEmpty())
+				g.Expect(getIPPoolAllocations(g, c)).To(Equal(tt.allocations))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(address).To(Equal(tt.wantAddress))
+			g.Expect(s.IBMPowerVSMachine.Status.IPClaims).To(Equal([]v1alpha4.PowerVSIPClaim{{Pool: "pool", Network: tt.network, Address: tt.wantAddress}}))
+			g.Expect(getIPPoolAllocations(g, c)).To(Equal(tt.wantAllocations))
+		})
+	}
+}
+
+func TestClaimAddressConcurrently(t *testing.T) {
+	g := NewWithT(t)
+	c := newIPPoolClient(t, newIPPool())
+
+	// As many machines as addresses claim at once, every claim conflicting with the others is retried.
+	const machines = 5
+	addresses := make([]string, machines)
+	errs := make([]error, machines)
+	var wg sync.WaitGroup
+	for i := 0; i < machines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			addresses[i], errs[i] = newIPPoolMachineScope(c, fmt.Sprintf("machine-%d", i)).ClaimAddress("pool", "network")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	g.Expect(addresses).To(ConsistOf("192.168.0.10", "192.168.0.11", "192.168.0.20", "192.168.0.21", "192.168.0.22"))
+	g.Expect(getIPPoolAllocations(g, c)).To(HaveLen(machines))
+
+	_, err := newIPPoolMachineScope(c, "machine-5").ClaimAddress("pool", "network")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestReleaseAddresses(t *testing.T) {
+	g := NewWithT(t)
+	c := newIPPoolClient(t, newIPPool(
+		v1alpha4.IPAllocation{Address: "192.168.0.10", Machine: "machine", Network: "network"},
+		v1alpha4.IPAllocation{Address: "192.168.0.11", Machine: "other", Network: "network"},
+		v1alpha4.IPAllocation{Address: "192.168.0.20", Machine: "machine", Network: "other-network"},
+		v1alpha4.IPAllocation{Address: "192.168.0.21", Machine: "machine", Network: "unclaimed"},
+	))
+	s := newIPPoolMachineScope(c, "machine")
+	s.IBMPowerVSMachine.Status.IPClaims = []v1alpha4.PowerVSIPClaim{
+		{Pool: "pool", Network: "network", Address: "192.168.0.10"},
+		{Pool: "pool", Network: "other-network", Address: "192.168.0.20"},
+		{Pool: "deleted", Network: "network", Address: "192.168.1.10"},
+	}
+
+	g.Expect(s.ReleaseAddresses()).To(Succeed())
+	g.Expect(s.IBMPowerVSMachine.Status.IPClaims).To(BeEmpty())
+	// Only the claims recorded by the machine are released.
+	g.Expect(getIPPoolAllocations(g, c)).To(Equal([]v1alpha4.IPAllocation{
+		{Address: "192.168.0.11", Machine: "other", Network: "network"},
+		{Address: "192.168.0.21", Machine: "machine", Network: "unclaimed"},
+	}))
+
+	// The addresses are released once.
+	g.Expect(s.ReleaseAddresses()).To(Succeed())
+	g.Expect(getIPPoolAllocations(g, c)).To(HaveLen(2))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"os"
+	"sort"
 	"strconv"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	utils "github.com/ppc64le-cloud/powervs-utils"
 
+	"github.com/IBM-Cloud/bluemix-go/crn"
 	"github.com/IBM-Cloud/power-go-client/ibmpisession"
 	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_networks"
 	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_p_vm_instances"
@@ -36,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -45,8 +49,10 @@ import (
 
 // PowerVSMachineScopeParams defines the input parameters used to create a new PowerVSMachineScope.
 type PowerVSMachineScopeParams struct {
-	Logger            logr.Logger
-	Client            client.Client
+	Logger logr.Logger
+	Client client.Client
+	// APIReader reads from the API server instead of the cache of Client, it is used to update IBMPowerVSIPPools.
+	APIReader         client.Reader
 	Cluster           *clusterv1.Cluster
 	Machine           *clusterv1.Machine
 	IBMPowerVSCluster *v1alpha4.IBMPowerVSCluster
@@ -57,9 +63,13 @@ type PowerVSMachineScopeParams struct {
 type PowerVSMachineScope struct {
 	logr.Logger
 	client      client.Client
+	apiReader   client.Reader
 	patchHelper *patch.Helper
+	// serviceInstanceCRN is the CRN of the service instance the machine is deployed in.
+	serviceInstanceCRN crn.CRN
 
 	IBMPowerVSClient  *IBMPowerVSClient
+	TaggingClient     *pkg.GlobalTaggingClient
 	Cluster           *clusterv1.Cluster
 	Machine           *clusterv1.Machine
 	IBMPowerVSCluster *v1alpha4.IBMPowerVSCluster
@@ -71,6 +81,9 @@ func NewPowerVSMachineScope(params PowerVSMachineScopeParams) (*PowerVSMachineSc
 	if params.Client == nil {
 		return nil, errors.New("client is required when creating a MachineScope")
 	}
+	if params.APIReader == nil {
+		return nil, errors.New("api reader is required when creating a MachineScope")
+	}
 	if params.Machine == nil {
 		return nil, errors.New("machine is required when creating a MachineScope")
 	}
@@ -86,7 +99,16 @@ func NewPowerVSMachineScope(params PowerVSMachineScopeParams) (*PowerVSMachineSc
 	}
 
 	m := params.IBMPowerVSMachine
-	client := pkg.NewClient()
+	if m.Spec.ServiceInstanceID == "" && params.IBMPowerVSCluster != nil {
+		m.Spec.ServiceInstanceID = params.IBMPowerVSCluster.GetServiceInstanceID()
+	}
+	if m.Spec.ServiceInstanceID == "" {
+		return nil, errors.New("service instance of the machine is not set and the cluster has no service instance")
+	}
+	client, err := pkg.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud client")
+	}
 
 	resource, err := client.ResourceClient.GetInstance(m.Spec.ServiceInstanceID)
 	if err != nil {
@@ -103,16 +125,29 @@ func NewPowerVSMachineScope(params PowerVSMachineScopeParams) (*PowerVSMachineSc
 		return nil, fmt.Errorf("failed to create NewIBMPowerVSClient")
 	}
 
+	authenticator, err := pkg.GetAuthenticator()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get authenticator")
+	}
+	// TODO: Will be removed once we find a better way of overriding the service endpoint
+	taggingClient, err := pkg.NewGlobalTaggingClient(authenticator, os.Getenv("TAGGING_SERVICE_ENDPOINT"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create IBM Cloud Global Tagging session")
+	}
+
 	helper, err := patch.NewHelper(params.IBMPowerVSMachine, params.Client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
 	}
 	return &PowerVSMachineScope{
-		Logger:      params.Logger,
-		client:      params.Client,
-		patchHelper: helper,
+		Logger:             params.Logger,
+		client:             params.Client,
+		apiReader:          params.APIReader,
+		patchHelper:        helper,
+		serviceInstanceCRN: resource.Crn,
 
 		IBMPowerVSClient:  c,
+		TaggingClient:     taggingClient,
 		Cluster:           params.Cluster,
 		Machine:           params.Machine,
 		IBMPowerVSMachine: params.IBMPowerVSMachine,
@@ -120,8 +155,12 @@ func NewPowerVSMachineScope(params PowerVSMachineScopeParams) (*PowerVSMachineSc
 	}, nil
 }
 
+// instanceRequestTimeout bounds a single PowerVS instance API request, the instance progress is polled
+// across reconciles instead of waiting on the request.
+const instanceRequestTimeout = 2 * time.Minute
+
 func (m *PowerVSMachineScope) ensureInstanceUnique(instanceName string) (*models.PVMInstanceReference, error) {
-	instances, err := m.IBMPowerVSClient.InstanceClient.GetAll(m.IBMPowerVSMachine.Spec.ServiceInstanceID, 60*time.Minute)
+	instances, err := m.IBMPowerVSClient.InstanceClient.GetAll(m.IBMPowerVSMachine.Spec.ServiceInstanceID, instanceRequestTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -133,20 +172,32 @@ func (m *PowerVSMachineScope) ensureInstanceUnique(instanceName string) (*models
 	return nil, nil
 }
 
-// CreateMachine creates a power vs machine
+// ReconcileInstanceID records the id of the instance of the machine when its creation was requested without the id
+// being returned, the instance is then looked up by the name of the machine.
+func (m *PowerVSMachineScope) ReconcileInstanceID() error {
+	if m.IBMPowerVSMachine.Status.InstanceID != "" {
+		return nil
+	}
+	instance, err := m.ensureInstanceUnique(m.IBMPowerVSMachine.Name)
+	if err != nil {
+		return err
+	}
+	if instance != nil && instance.PvmInstanceID != nil {
+		m.IBMPowerVSMachine.Status.InstanceID = *instance.PvmInstanceID
+	}
+	return nil
+}
+
+// CreateMachine creates a power vs machine, it returns the reference to the instance once its creation is
+// requested without waiting for the instance to become active.
 func (m *PowerVSMachineScope) CreateMachine() (*models.PVMInstanceReference, error) {
 	s := m.IBMPowerVSMachine.Spec
 
-	instanceReply, err := m.ensureInstanceUnique(m.IBMPowerVSMachine.Name)
-	if err != nil {
+	if err := m.ReconcileInstanceID(); err != nil {
 		return nil, err
-	} else if instanceReply != nil {
-		//TODO need a reasonable wrapped error
-		return instanceReply, nil
 	}
-	cloudInitData, err := m.GetBootstrapData()
-	if err != nil {
-		return nil, err
+	if id := m.IBMPowerVSMachine.Status.InstanceID; id != "" {
+		return &models.PVMInstanceReference{PvmInstanceID: &id}, nil
 	}
 
 	memory, err := strconv.ParseFloat(s.Memory, 64)
@@ -163,34 +214,112 @@ func (m *PowerVSMachineScope) CreateMachine() (*models.PVMInstanceReference, err
 		return nil, fmt.Errorf("error getting image ID: %v", err)
 	}
 
-	networkID, err := getNetworkID(s.Network, m)
+	sshKey := s.SSHKey
+	if sshKey == "" && m.IBMPowerVSCluster != nil && m.IBMPowerVSCluster.Status.SSHKey != nil {
+		sshKey = m.IBMPowerVSCluster.Status.SSHKey.Name
+	}
+
+	placementGroupID, err := m.getPlacementGroupID()
+	if err != nil {
+		return nil, fmt.Errorf("error getting placement group ID: %v", err)
+	}
+
+	sharedProcessorPoolID, err := m.getSharedProcessorPoolID()
+	if err != nil {
+		return nil, fmt.Errorf("error getting shared processor pool ID: %v", err)
+	}
+
+	if err := m.validateStorage(); err != nil {
+		return nil, err
+	}
+
+	volumeIDs, ready, err := m.ReconcileVolumes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reconcile volumes")
+	}
+	if !ready {
+		m.Info("Waiting for the volumes to be available")
+		return nil, nil
+	}
+
+	// The addresses are claimed once the instance is about to be created, the networks are recorded before its
+	// creation is requested since the instance may only be found by name afterwards.
+	networks, err := m.getNetworks()
+	if err != nil {
+		return nil, err
+	}
+	m.IBMPowerVSMachine.Status.NetworkIDs = make([]string, 0, len(networks))
+	for _, network := range networks {
+		m.IBMPowerVSMachine.Status.NetworkIDs = append(m.IBMPowerVSMachine.Status.NetworkIDs, *network.NetworkID)
+	}
+
+	cloudInitData, err := m.GetBootstrapData()
 	if err != nil {
-		return nil, fmt.Errorf("error getting network ID: %v", err)
+		return nil, err
 	}
 
 	params := &p_cloud_p_vm_instances.PcloudPvminstancesPostParams{
 		Body: &models.PVMInstanceCreate{
-			ImageID:     imageID,
-			KeyPairName: s.SSHKey,
-			Networks: []*models.PVMInstanceAddNetwork{
-				{
-					NetworkID: networkID,
-					//IPAddress: address,
-				},
-			},
-			ServerName: &m.IBMPowerVSMachine.Name,
-			Memory:     &memory,
-			Processors: &cores,
-			ProcType:   &s.ProcType,
-			SysType:    s.SysType,
-			UserData:   cloudInitData,
+			ImageID:         imageID,
+			KeyPairName:     sshKey,
+			Networks:        networks,
+			ServerName:      &m.IBMPowerVSMachine.Name,
+			Memory:          &memory,
+			Processors:      &cores,
+			ProcType:        &s.ProcType,
+			SysType:         s.SysType,
+			UserData:        cloudInitData,
+			VolumeIds:       volumeIDs,
+			PlacementGroup:  placementGroupID,
+			StorageType:     s.StorageType,
+			StoragePool:     s.StoragePool,
+			StorageAffinity: storageAffinity(s.StorageAffinity),
 		},
 	}
-	_, err = m.IBMPowerVSClient.InstanceClient.Create(params, s.ServiceInstanceID, time.Hour)
+	var instances *models.PVMInstanceList
+	if sharedProcessorPoolID != "" {
+		instances, err = m.IBMPowerVSClient.SharedProcessorPoolClient.CreateInstance(params.Body, sharedProcessorPoolID, s.ServiceInstanceID)
+	} else {
+		instances, err = m.IBMPowerVSClient.InstanceClient.Create(params, s.ServiceInstanceID, instanceRequestTimeout)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return nil, nil
+	if instances == nil || len(*instances) == 0 || (*instances)[0].PvmInstanceID == nil {
+		// The instance is looked up by its name on the next reconcile.
+		return nil, nil
+	}
+	id := *(*instances)[0].PvmInstanceID
+	m.IBMPowerVSMachine.Status.InstanceID = id
+	return &models.PVMInstanceReference{PvmInstanceID: &id}, nil
+}
+
+// GetMachine returns the instance with the given id.
+func (m *PowerVSMachineScope) GetMachine(id string) (*models.PVMInstance, error) {
+	return m.IBMPowerVSClient.InstanceClient.Get(id, m.IBMPowerVSMachine.Spec.ServiceInstanceID, instanceRequestTimeout)
+}
+
+// ReconcileTags attaches the cluster and machine tags to the instance. The instance is only tagged when the tags
+// changed since the last reconciliation, re-attaching the tags detached in the meantime. Tags removed from the spec
+// are not detached.
+func (m *PowerVSMachineScope) ReconcileTags(instanceID string) error {
+	instanceCRN := m.serviceInstanceCRN
+	instanceCRN.ResourceType = "pvm-instance"
+	instanceCRN.Resource = instanceID
+
+	tags := clusterTags(m.Cluster, machineRole(m.Machine), m.IBMPowerVSMachine.Spec.Tags)
+	hash, err := tagsHash([]string{instanceCRN.String()}, tags, m.IBMPowerVSMachine.Spec.AccessTags)
+	if err != nil {
+		return err
+	}
+	if m.IBMPowerVSMachine.Status.TagsHash == hash {
+		return nil
+	}
+	if err := reconcileTags(m.TaggingClient, instanceCRN.String(), tags, m.IBMPowerVSMachine.Spec.AccessTags); err != nil {
+		return err
+	}
+	m.IBMPowerVSMachine.Status.TagsHash = hash
+	return nil
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
@@ -205,7 +334,7 @@ func (m *PowerVSMachineScope) PatchObject() error {
 
 // DeleteMachine deletes the power vs machine associated with machine instance id and service instance id.
 func (m *PowerVSMachineScope) DeleteMachine() error {
-	return m.IBMPowerVSClient.InstanceClient.Delete(m.IBMPowerVSMachine.Status.InstanceID, m.IBMPowerVSMachine.Spec.ServiceInstanceID, time.Hour)
+	return m.IBMPowerVSClient.InstanceClient.Delete(m.IBMPowerVSMachine.Status.InstanceID, m.IBMPowerVSMachine.Spec.ServiceInstanceID, instanceRequestTimeout)
 }
 
 // GetBootstrapData returns the base64 encoded bootstrap data from the secret in the Machine's bootstrap.dataSecretName
@@ -225,11 +354,89 @@ func (m *PowerVSMachineScope) GetBootstrapData() (string, error) {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
 
-	return base64.StdEncoding.EncodeToString(value), nil
+	format := string(secret.Data["format"])
+
+	if len(m.IBMPowerVSMachine.Spec.AdditionalUserData) > 0 {
+		if format == bootstrapFormatIgnition {
+			return "", errors.New("additional user data can not be merged with Ignition bootstrap data")
+		}
+		parts, err := getAdditionalUserData(m.client, m.IBMPowerVSMachine.Namespace, m.IBMPowerVSMachine.Spec.AdditionalUserData)
+		if err != nil {
+			return "", err
+		}
+		if value, err = mergeUserData(value, parts); err != nil {
+			return "", err
+		}
+	}
+
+	var dataURL string
+	if store, err := m.bootstrapDataStore(); err != nil {
+		return "", err
+	} else if store != nil {
+		objectKey := bootstrapDataObjectKey(m.IBMPowerVSMachine.Namespace, m.IBMPowerVSMachine.Name)
+		if dataURL, err = store.Put(objectKey, value); err != nil {
+			return "", err
+		}
+		m.IBMPowerVSMachine.Status.BootstrapDataObject = objectKey
+	}
+
+	// PowerVS has no metadata service, only the hostname is generated by the provider.
+	userData, err := bootstrapUserData(format, value, dataURL, ignitionOptions{
+		Hostname: m.IBMPowerVSMachine.Name,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate user data for IBMPowerVSMachine %s/%s", m.IBMPowerVSMachine.Namespace, m.IBMPowerVSMachine.Name)
+	}
+
+	return base64.StdEncoding.EncodeToString(userData), nil
+}
+
+// bootstrapDataStore returns the store of the bootstrap data, or nil if the cluster delivers it in the user data.
+func (m *PowerVSMachineScope) bootstrapDataStore() (*bootstrapDataStore, error) {
+	if m.IBMPowerVSCluster == nil || m.IBMPowerVSCluster.Spec.BootstrapStorage == nil {
+		return nil, nil
+	}
+	return newBootstrapDataStore(m.IBMPowerVSCluster.Spec.BootstrapStorage, m.Cluster.Name)
+}
+
+// DeleteBootstrapData deletes the bootstrap data uploaded to Cloud Object Storage for the instance.
+func (m *PowerVSMachineScope) DeleteBootstrapData() error {
+	if m.IBMPowerVSMachine.Status.BootstrapDataObject == "" {
+		return nil
+	}
+	store, err := m.bootstrapDataStore()
+	if err != nil {
+		return err
+	} else if store != nil {
+		if err := store.Delete(m.IBMPowerVSMachine.Status.BootstrapDataObject); err != nil {
+			return errors.Wrap(err, "failed to delete bootstrap data")
+		}
+	}
+	m.IBMPowerVSMachine.Status.BootstrapDataObject = ""
+	return nil
+}
+
+// GetImage returns the IBMPowerVSImage referenced by the machine.
+func (m *PowerVSMachineScope) GetImage() (*v1alpha4.IBMPowerVSImage, error) {
+	image := &v1alpha4.IBMPowerVSImage{}
+	key := types.NamespacedName{Namespace: m.IBMPowerVSMachine.Namespace, Name: m.IBMPowerVSMachine.Spec.ImageRef.Name}
+	if err := m.client.Get(context.TODO(), key, image); err != nil {
+		return nil, errors.Wrapf(err, "failed to get IBMPowerVSImage %s", key)
+	}
+	return image, nil
 }
 
 func getImageID(image v1alpha4.IBMPowerVSResourceReference, m *PowerVSMachineScope) (*string, error) {
-	if image.ID != nil {
+	if m.IBMPowerVSMachine.Spec.ImageRef != nil {
+		ref, err := m.GetImage()
+		if err != nil {
+			return nil, err
+		}
+		if !ref.Status.Ready {
+			return nil, fmt.Errorf("IBMPowerVSImage %s is not ready", ref.Name)
+		}
+		return &ref.Status.ImageID, nil
+	} else if image.ID != nil {
 		return image.ID, nil
 	} else if image.Name != nil {
 		images, err := m.GetImages()
@@ -253,6 +460,215 @@ func (m *PowerVSMachineScope) GetImages() (*models.Images, error) {
 	return m.IBMPowerVSClient.ImageClient.GetAll(m.IBMPowerVSMachine.Spec.ServiceInstanceID)
 }
 
+// getPlacementGroupID returns the ID of the placement group of the machine, a name is looked up in the placement
+// groups of the cluster first.
+func (m *PowerVSMachineScope) getPlacementGroupID() (string, error) {
+	ref := m.IBMPowerVSMachine.Spec.PlacementGroup
+	if ref == nil {
+		return "", nil
+	}
+	if ref.ID != nil {
+		return *ref.ID, nil
+	}
+	if ref.Name == nil {
+		return "", fmt.Errorf("both ID and Name can't be nil")
+	}
+	if m.IBMPowerVSCluster != nil {
+		for _, group := range m.IBMPowerVSCluster.Status.PlacementGroups {
+			if group.Name == *ref.Name {
+				return group.ID, nil
+			}
+		}
+	}
+	groups, err := m.IBMPowerVSClient.PlacementGroupClient.GetAll(m.IBMPowerVSMachine.Spec.ServiceInstanceID)
+	if err != nil {
+		return "", err
+	}
+	for _, group := range groups.PlacementGroups {
+		if *group.Name == *ref.Name {
+			return *group.ID, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find a placement group ID")
+}
+
+// getNetworks returns the networks of the instance create request, claiming the addresses from the IP pools.
+// A network without reference is the network of the IP pool, or else the network of the cluster.
+func (m *PowerVSMachineScope) getNetworks() ([]*models.PVMInstanceAddNetwork, error) {
+	s := m.IBMPowerVSMachine.Spec
+	specs := s.Networks
+	if len(specs) == 0 {
+		specs = []v1alpha4.PowerVSMachineNetwork{{IBMPowerVSResourceReference: s.Network}}
+	} else if s.Network.ID != nil || s.Network.Name != nil {
+		return nil, fmt.Errorf("network and networks cannot be set together")
+	}
+
+	networks := make([]*models.PVMInstanceAddNetwork, 0, len(specs))
+	for _, spec := range specs {
+		ref := spec.IBMPowerVSResourceReference
+		address := spec.IPAddress
+		if spec.IPPool != nil {
+			if address != "" {
+				return nil, fmt.Errorf("ipAddress and ipPool cannot be set together")
+			}
+			if ref.ID == nil && ref.Name == nil {
+				pool, err := m.GetIPPool(spec.IPPool.Name)
+				if err != nil {
+					return nil, err
+				}
+				ref = pool.Spec.Network
+			}
+		}
+		if ref.ID == nil && ref.Name == nil && m.IBMPowerVSCluster != nil && m.IBMPowerVSCluster.Status.Network != nil {
+			ref.ID = &m.IBMPowerVSCluster.Status.Network.ID
+		}
+		networkID, err := getNetworkID(ref, m)
+		if err != nil {
+			return nil, fmt.Errorf("error getting network ID: %v", err)
+		}
+		if spec.IPPool != nil {
+			if address, err = m.ClaimAddress(spec.IPPool.Name, *networkID); err != nil {
+				return nil, err
+			}
+		}
+		networks = append(networks, &models.PVMInstanceAddNetwork{
+			NetworkID: networkID,
+			IPAddress: address,
+		})
+	}
+	return networks, nil
+}
+
+// SetAddresses sets the addresses of the instance as node addresses, ordered as the networks of the spec
+// followed by the other networks of the instance.
+func (m *PowerVSMachineScope) SetAddresses(instance *models.PVMInstance) {
+	order := map[string]int{}
+	for i, id := range m.IBMPowerVSMachine.Status.NetworkIDs {
+		order[id] = i
+	}
+	rank := func(network *models.PVMInstanceNetwork) int {
+		if i, ok := order[network.NetworkID]; ok {
+			return i
+		}
+		return len(order)
+	}
+	networks := append([]*models.PVMInstanceNetwork(nil), instance.Networks...)
+	sort.SliceStable(networks, func(i, j int) bool {
+		if rank(networks[i]) != rank(networks[j]) {
+			return rank(networks[i]) < rank(networks[j])
+		}
+		if networks[i].NetworkID != networks[j].NetworkID {
+			return networks[i].NetworkID < networks[j].NetworkID
+		}
+		return networks[i].IPAddress < networks[j].IPAddress
+	})
+
+	var addresses []corev1.NodeAddress
+	for _, network := range networks {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalIP,
+			Address: network.IPAddress,
+		})
+		if network.ExternalIP != "" {
+			addresses = append(addresses, corev1.NodeAddress{
+				Type:    corev1.NodeExternalIP,
+				Address: network.ExternalIP,
+			})
+		}
+	}
+	m.IBMPowerVSMachine.Status.Addresses = addresses
+}
+
+// getSharedProcessorPoolID returns the ID of the shared processor pool of the machine, a name is looked up in the
+// shared processor pool of the cluster first.
+func (m *PowerVSMachineScope) getSharedProcessorPoolID() (string, error) {
+	ref := m.IBMPowerVSMachine.Spec.SharedProcessorPool
+	if ref == nil {
+		return "", nil
+	}
+	if m.IBMPowerVSMachine.Spec.ProcType == models.PVMInstanceCreateProcTypeDedicated {
+		return "", fmt.Errorf("shared processor pool requires a shared or capped processor type")
+	}
+	if ref.ID != nil {
+		return *ref.ID, nil
+	}
+	if ref.Name == nil {
+		return "", fmt.Errorf("both ID and Name can't be nil")
+	}
+	if m.IBMPowerVSCluster != nil {
+		if pool := m.IBMPowerVSCluster.Status.SharedProcessorPool; pool != nil && pool.Name == *ref.Name {
+			return pool.ID, nil
+		}
+	}
+	pools, err := m.IBMPowerVSClient.SharedProcessorPoolClient.GetAll(m.IBMPowerVSMachine.Spec.ServiceInstanceID)
+	if err != nil {
+		return "", err
+	}
+	for _, pool := range pools {
+		if pool.Name == *ref.Name {
+			return pool.ID, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find a shared processor pool ID")
+}
+
+// validateStorage checks that the storage type and pool of the boot volume are available in the service instance.
+func (m *PowerVSMachineScope) validateStorage() error {
+	s := m.IBMPowerVSMachine.Spec
+	if s.StoragePool != "" && s.StorageAffinity != nil {
+		return fmt.Errorf("storagePool and storageAffinity cannot be set together")
+	}
+	if s.StorageAffinity != nil && (s.StorageAffinity.Volume == "") == (s.StorageAffinity.Instance == "") {
+		return fmt.Errorf("one of volume or instance must be set in storageAffinity")
+	}
+	if s.StorageType == "" && s.StoragePool == "" {
+		return nil
+	}
+
+	pools, err := m.IBMPowerVSClient.StorageClient.GetAllStoragePools(s.ServiceInstanceID, TIMEOUT)
+	if err != nil {
+		return errors.Wrap(err, "failed to get storage pools")
+	}
+	for _, pool := range pools.StoragePoolsCapacity {
+		if (s.StoragePool == "" || pool.PoolName == s.StoragePool) && (s.StorageType == "" || pool.StorageType == s.StorageType) {
+			conditions.MarkTrue(m.IBMPowerVSMachine, v1alpha4.StorageValidCondition)
+			return nil
+		}
+	}
+	var reason string
+	switch {
+	case s.StoragePool == "":
+		reason = fmt.Sprintf("no storage pool holds storage type %s", s.StorageType)
+	case s.StorageType == "":
+		reason = fmt.Sprintf("storage pool %s not found", s.StoragePool)
+	default:
+		reason = fmt.Sprintf("storage pool %s with storage type %s not found", s.StoragePool, s.StorageType)
+	}
+	conditions.MarkFalse(m.IBMPowerVSMachine, v1alpha4.StorageValidCondition, v1alpha4.StorageNotFoundReason, clusterv1.ConditionSeverityError,
+		"%s", reason)
+	return errors.New(reason)
+}
+
+// storageAffinity returns the storage affinity of the boot volume for the instance create request.
+func storageAffinity(affinity *v1alpha4.PowerVSStorageAffinity) *models.StorageAffinity {
+	if affinity == nil {
+		return nil
+	}
+	policy := affinity.Policy
+	result := &models.StorageAffinity{AffinityPolicy: &policy}
+	switch {
+	case policy == models.StorageAffinityAffinityPolicyAffinity && affinity.Volume != "":
+		result.AffinityVolume = &affinity.Volume
+	case policy == models.StorageAffinityAffinityPolicyAffinity:
+		result.AffinityPVMInstance = &affinity.Instance
+	case affinity.Volume != "":
+		result.AntiAffinityVolumes = []string{affinity.Volume}
+	default:
+		result.AntiAffinityPVMInstances = []string{affinity.Instance}
+	}
+	return result
+}
+
 func getNetworkID(network v1alpha4.IBMPowerVSResourceReference, m *PowerVSMachineScope) (*string, error) {
 	if network.ID != nil {
 		return network.ID, nil
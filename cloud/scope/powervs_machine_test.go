@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func TestValidateStorage(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       v1alpha4.IBMPowerVSMachineSpec
+		wantErr    string
+		wantReason string
+		wantValid  bool
+	}{
+		{
+			name: "does nothing without storage type or pool",
+		},
+		{
+			name:      "accepts an available storage type",
+			spec:      v1alpha4.IBMPowerVSMachineSpec{StorageType: "tier3"},
+			wantValid: true,
+		},
+		{
+			name:      "accepts a storage pool holding the storage type",
+			spec:      v1alpha4.IBMPowerVSMachineSpec{StorageType: "tier1", StoragePool: "pool-1"},
+			wantValid: true,
+		},
+		{
+			name:       "rejects an unavailable storage type",
+			spec:       v1alpha4.IBMPowerVSMachineSpec{StorageType: "tier5k"},
+			wantErr:    "no storage pool holds storage type tier5k",
+			wantReason: v1alpha4.StorageNotFoundReason,
+		},
+		{
+			name:       "rejects a storage pool without the storage type",
+			spec:       v1alpha4.IBMPowerVSMachineSpec{StorageType: "tier3", StoragePool: "pool-1"},
+			wantErr:    "storage pool pool-1 with storage type tier3 not found",
+			wantReason: v1alpha4.StorageNotFoundReason,
+		},
+		{
+			name: "rejects a storage pool with a storage affinity",
+			spec: v1alpha4.IBMPowerVSMachineSpec{
+				StoragePool:     "pool-1",
+				StorageAffinity: &v1alpha4.PowerVSStorageAffinity{Policy: "affinity", Volume: "volume"},
+			},
+			wantErr: "storagePool and storageAffinity cannot be set together",
+		},
+		{
+			name: "rejects a storage affinity to both a volume and an instance",
+			spec: v1alpha4.IBMPowerVSMachineSpec{
+				StorageAffinity: &v1alpha4.PowerVSStorageAffinity{Policy: "affinity", Volume: "volume", Instance: "instance"},
+			},
+			wantErr: "one of volume or instance must be set in storageAffinity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/storage-capacity/storage-pools", reply(http.StatusOK, map[string]interface{}{
+				"storagePoolsCapacity": []map[string]interface{}{
+					{"poolName": "pool-1", "storageType": "tier1", "maxAllocationSize": 1000},
+					{"poolName": "pool-2", "storageType": "tier3", "maxAllocationSize": 1000},
+				},
+			}))
+			s := newPowerVSMachineScope(t, powervs, tt.spec)
+
+			err := s.validateStorage()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(conditions.IsTrue(s.IBMPowerVSMachine, v1alpha4.StorageValidCondition)).To(Equal(tt.wantValid))
+			g.Expect(conditions.GetReason(s.IBMPowerVSMachine, v1alpha4.StorageValidCondition)).To(Equal(tt.wantReason))
+		})
+	}
+}
+
+func TestCreateMachine(t *testing.T) {
+	tests := []struct {
+		name           string
+		instances      []map[string]interface{}
+		wantInstanceID string
+	}{
+		{
+			name:           "records the id of the instance found by name",
+			instances:      []map[string]interface{}{{"pvmInstanceID": "instance-id", "serverName": "machine", "status": "BUILD"}},
+			wantInstanceID: "instance-id",
+		},
+		{
+			name:      "claims no address while the volumes are not available",
+			instances: []map[string]interface{}{{"pvmInstanceID": "other-id", "serverName": "other", "status": "ACTIVE"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/pvm-instances", reply(http.StatusOK, map[string]interface{}{"pvmInstances": tt.instances}))
+			powervs.handle(http.MethodGet, "/volumes", reply(http.StatusOK, volumes()))
+			powervs.handle(http.MethodPost, "/volumes", reply(http.StatusAccepted, volume("data-id", "machine-data", "creating")))
+			c := newIPPoolClient(t, newIPPool())
+			s := newPowerVSMachineScope(t, powervs, v1alpha4.IBMPowerVSMachineSpec{
+				Image:      v1alpha4.IBMPowerVSResourceReference{ID: pointer.String("image")},
+				Memory:     "4",
+				Processors: "0.5",
+				Networks: []v1alpha4.PowerVSMachineNetwork{{
+					IBMPowerVSResourceReference: v1alpha4.IBMPowerVSResourceReference{ID: pointer.String("network")},
+					IPPool:                      &corev1.LocalObjectReference{Name: "pool"},
+				}},
+				Volumes: []v1alpha4.PowerVSVolume{{Name: "data", Size: 10}},
+			})
+			s.client, s.apiReader = c, c
+
+			instance, err := s.CreateMachine()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.IBMPowerVSMachine.Status.InstanceID).To(Equal(tt.wantInstanceID))
+			if tt.wantInstanceID != "" {
+				g.Expect(instance.PvmInstanceID).To(Equal(pointer.String(tt.wantInstanceID)))
+				g.Expect(powervs.requests).NotTo(ContainElement("POST /volumes"))
+			} else {
+				g.Expect(instance).To(BeNil())
+				g.Expect(powervs.requests).To(ContainElement("POST /volumes"))
+			}
+			g.Expect(powervs.requests).NotTo(ContainElement("POST /pvm-instances"))
+			g.Expect(s.IBMPowerVSMachine.Status.IPClaims).To(BeEmpty())
+			g.Expect(getIPPoolAllocations(g, c)).To(BeEmpty())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_p_vm_instances"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+const (
+	instanceStateActive       = "ACTIVE"
+	instanceStateShutoff      = "SHUTOFF"
+	instanceStateResize       = "RESIZE"
+	instanceStateVerifyResize = "VERIFY_RESIZE"
+)
+
+// ReconcileResize resizes the instance in place to the processor type, processors and memory of the spec
+// when ResizeInPlace is set. It returns true once the instance matches the spec or the resize is refused.
+func (m *PowerVSMachineScope) ReconcileResize(instance *models.PVMInstance) (bool, error) {
+	s := m.IBMPowerVSMachine.Spec
+	if !s.ResizeInPlace {
+		return true, nil
+	}
+
+	memory, err := strconv.ParseFloat(s.Memory, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert memory(%s) to float64", s.Memory)
+	}
+	processors, err := strconv.ParseFloat(s.Processors, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert Processors(%s) to float64", s.Processors)
+	}
+
+	state := *instance.Status
+	if state == instanceStateResize || state == instanceStateVerifyResize {
+		conditions.MarkFalse(m.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition, v1alpha4.InstanceResizingReason, clusterv1.ConditionSeverityInfo,
+			"instance is in %s state", state)
+		return false, nil
+	}
+
+	procTypeChanged := s.ProcType != "" && s.ProcType != *instance.ProcType
+	if !procTypeChanged && processors == *instance.Processors && memory == *instance.Memory {
+		conditions.MarkTrue(m.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition)
+		return true, nil
+	}
+
+	if state != instanceStateActive && state != instanceStateShutoff {
+		conditions.MarkFalse(m.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition, v1alpha4.InstanceResizingReason, clusterv1.ConditionSeverityInfo,
+			"waiting for the instance in %s state to be resized", state)
+		return false, nil
+	}
+
+	if reason, err := m.checkResize(instance, processors, memory, procTypeChanged); err != nil {
+		return false, err
+	} else if reason != "" {
+		conditions.MarkFalse(m.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition, v1alpha4.InstanceResizeNotSupportedReason, clusterv1.ConditionSeverityError,
+			"%s", reason)
+		return true, nil
+	}
+
+	body := &models.PVMInstanceUpdate{
+		Processors: processors,
+		Memory:     memory,
+	}
+	if procTypeChanged {
+		body.ProcType = s.ProcType
+	}
+	params := p_cloud_p_vm_instances.NewPcloudPvminstancesPutParamsWithTimeout(instanceRequestTimeout).
+		WithCloudInstanceID(s.ServiceInstanceID).WithPvmInstanceID(*instance.PvmInstanceID).WithBody(body)
+	if _, err := m.IBMPowerVSClient.InstanceClient.Update(*instance.PvmInstanceID, s.ServiceInstanceID, params, instanceRequestTimeout); err != nil {
+		return false, errors.Wrapf(err, "failed to resize instance %s", *instance.PvmInstanceID)
+	}
+	procType := *instance.ProcType
+	if procTypeChanged {
+		procType = s.ProcType
+	}
+	m.Info("Resizing instance", "processors", processors, "memory", memory, "procType", procType)
+	conditions.MarkFalse(m.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition, v1alpha4.InstanceResizingReason, clusterv1.ConditionSeverityInfo,
+		"resizing the instance to %v %s processors and %vGB of memory", processors, procType, memory)
+	return false, nil
+}
+
+// checkResize returns the reason why the instance cannot be resized in place, or an empty string.
+func (m *PowerVSMachineScope) checkResize(instance *models.PVMInstance, processors, memory float64, procTypeChanged bool) (string, error) {
+	if procTypeChanged && *instance.Status != instanceStateShutoff {
+		return fmt.Sprintf("processor type can only be changed from %s to %s while the instance is shut off", *instance.ProcType, m.IBMPowerVSMachine.Spec.ProcType), nil
+	}
+	// A running instance is resized within the bounds of its partition profile.
+	if *instance.Status == instanceStateActive {
+		if processors < instance.Minproc || processors > instance.Maxproc {
+			return fmt.Sprintf("processors %v are out of the range %v-%v of the running instance", processors, instance.Minproc, instance.Maxproc), nil
+		}
+		if memory < instance.Minmem || memory > instance.Maxmem {
+			return fmt.Sprintf("memory %v is out of the range %v-%v of the running instance", memory, instance.Minmem, instance.Maxmem), nil
+		}
+	}
+
+	// The added processors and memory must be available on a system of the system type of the instance.
+	pools, err := m.IBMPowerVSClient.SystemPoolClient.Get(m.IBMPowerVSMachine.Spec.ServiceInstanceID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get system pools")
+	}
+	pool, ok := pools[instance.SysType]
+	if !ok {
+		return fmt.Sprintf("system type %s is not available", instance.SysType), nil
+	}
+	if pool.MaxCoresAvailable != nil && pool.MaxCoresAvailable.Cores != nil && processors-*instance.Processors > *pool.MaxCoresAvailable.Cores {
+		return fmt.Sprintf("system type %s has %v processors available, %v more are requested",
+			instance.SysType, *pool.MaxCoresAvailable.Cores, processors-*instance.Processors), nil
+	}
+	if pool.MaxMemoryAvailable != nil && pool.MaxMemoryAvailable.Memory != nil && memory-*instance.Memory > float64(*pool.MaxMemoryAvailable.Memory) {
+		return fmt.Sprintf("system type %s has %vGB of memory available, %vGB more are requested",
+			instance.SysType, *pool.MaxMemoryAvailable.Memory, memory-*instance.Memory), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func TestReconcileResize(t *testing.T) {
+	instance := func(state string) *models.PVMInstance {
+		return &models.PVMInstance{
+			PvmInstanceID: pointer.String("instance"),
+			Status:        pointer.String(state),
+			ProcType:      pointer.String("shared"),
+			Processors:    pointer.Float64(0.5),
+			Memory:        pointer.Float64(4),
+			Minproc:       0.25,
+			Maxproc:       2,
+			Minmem:        2,
+			Maxmem:        16,
+			SysType:       "s922",
+		}
+	}
+
+	tests := []struct {
+		name        string
+		spec        v1alpha4.IBMPowerVSMachineSpec
+		instance    *models.PVMInstance
+		wantDone    bool
+		wantReason  string
+		wantMessage string
+		wantUpdate  map[string]interface{}
+	}{
+		{
+			name:     "does nothing unless resizing in place",
+			spec:     v1alpha4.IBMPowerVSMachineSpec{Processors: "1", Memory: "8"},
+			instance: instance(instanceStateActive),
+			wantDone: true,
+		},
+		{
+			name:     "reports the instance matching the spec",
+			spec:     v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "0.5", Memory: "4", ProcType: "shared"},
+			instance: instance(instanceStateActive),
+			wantDone: true,
+		},
+		{
+			name:        "resizes the running instance within its bounds",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "1", Memory: "8"},
+			instance:    instance(instanceStateActive),
+			wantReason:  v1alpha4.InstanceResizingReason,
+			wantMessage: "resizing the instance to 1 shared processors and 8GB of memory",
+			wantUpdate:  map[string]interface{}{"processors": float64(1), "memory": float64(8)},
+		},
+		{
+			name:        "changes the processor type of the shut off instance",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "0.5", Memory: "4", ProcType: "dedicated"},
+			instance:    instance(instanceStateShutoff),
+			wantReason:  v1alpha4.InstanceResizingReason,
+			wantMessage: "resizing the instance to 0.5 dedicated processors and 4GB of memory",
+			wantUpdate:  map[string]interface{}{"processors": float64(0.5), "memory": float64(4), "procType": "dedicated"},
+		},
+		{
+			name:        "waits for the resize in progress",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "1", Memory: "8"},
+			instance:    instance(instanceStateResize),
+			wantReason:  v1alpha4.InstanceResizingReason,
+			wantMessage: "instance is in RESIZE state",
+		},
+		{
+			name:        "refuses to change the processor type of the running instance",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "0.5", Memory: "4", ProcType: "dedicated"},
+			instance:    instance(instanceStateActive),
+			wantDone:    true,
+			wantReason:  v1alpha4.InstanceResizeNotSupportedReason,
+			wantMessage: "processor type can only be changed from shared to dedicated while the instance is shut off",
+		},
+		{
+			name:        "refuses processors out of the range of the running instance",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "4", Memory: "4"},
+			instance:    instance(instanceStateActive),
+			wantDone:    true,
+			wantReason:  v1alpha4.InstanceResizeNotSupportedReason,
+			wantMessage: "processors 4 are out of the range 0.25-2 of the running instance",
+		},
+		{
+			name:        "refuses memory beyond the capacity of the system type",
+			spec:        v1alpha4.IBMPowerVSMachineSpec{ResizeInPlace: true, Processors: "0.5", Memory: "64"},
+			instance:    instance(instanceStateShutoff),
+			wantDone:    true,
+			wantReason:  v1alpha4.InstanceResizeNotSupportedReason,
+			wantMessage: "system type s922 has 32GB of memory available, 60GB more are requested",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var update map[string]interface{}
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/system-pools", reply(http.StatusOK, map[string]interface{}{
+				"s922": map[string]interface{}{
+					"maxCoresAvailable":  map[string]interface{}{"cores": 4, "memory": 256},
+					"maxMemoryAvailable": map[string]interface{}{"cores": 1, "memory": 32},
+					"systems":            []interface{}{},
+				},
+			}))
+			powervs.handle(http.MethodPut, "/pvm-instances/instance", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				update = body
+				return http.StatusAccepted, map[string]interface{}{}
+			})
+			s := newPowerVSMachineScope(t, powervs, tt.spec)
+
+			done, err := s.ReconcileResize(tt.instance)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(done).To(Equal(tt.wantDone))
+			g.Expect(update).To(Equal(tt.wantUpdate))
+			if tt.wantReason == "" {
+				g.Expect(conditions.GetReason(s.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition)).To(BeEmpty())
+				return
+			}
+			g.Expect(conditions.GetReason(s.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition)).To(Equal(tt.wantReason))
+			g.Expect(conditions.GetMessage(s.IBMPowerVSMachine, v1alpha4.InstanceResizedCondition)).To(Equal(tt.wantMessage))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+)
+
+// SharedProcessorPool is a shared processor pool of a service instance.
+type SharedProcessorPool struct {
+	ID             string  `json:"id,omitempty"`
+	Name           string  `json:"name,omitempty"`
+	HostGroup      string  `json:"hostGroup,omitempty"`
+	ReservedCores  int64   `json:"reservedCores,omitempty"`
+	AllocatedCores float64 `json:"allocatedCores,omitempty"`
+	AvailableCores float64 `json:"availableCores,omitempty"`
+	Status         string  `json:"status,omitempty"`
+}
+
+// SharedProcessorPoolServer is an instance of a shared processor pool.
+type SharedProcessorPoolServer struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// SharedProcessorPoolDetail is a shared processor pool with its instances.
+type SharedProcessorPoolDetail struct {
+	SharedProcessorPool *SharedProcessorPool         `json:"sharedProcessorPool"`
+	Servers             []*SharedProcessorPoolServer `json:"servers"`
+}
+
+// SharedProcessorPoolClient manages the shared processor pools of a service instance,
+// which the power-go-client does not support yet.
+type SharedProcessorPoolClient struct {
+	session *ibmpisession.IBMPISession
+}
+
+// NewSharedProcessorPoolClient creates and returns a shared processor pool client.
+func NewSharedProcessorPoolClient(session *ibmpisession.IBMPISession) *SharedProcessorPoolClient {
+	return &SharedProcessorPoolClient{session: session}
+}
+
+// Get returns the shared processor pool with its instances, or nil when it does not exist.
+func (c *SharedProcessorPoolClient) Get(id, cloudInstanceID string) (*SharedProcessorPoolDetail, error) {
+	result := &SharedProcessorPoolDetail{}
+	err := c.submit("pcloud.sharedprocessorpools.get", http.MethodGet, "/pcloud/v1/cloud-instances/{cloud_instance_id}/shared-processor-pools/{shared_processor_pool_id}",
+		cloudInstanceID, id, nil, result)
+	if apiErr, ok := err.(*runtime.APIError); ok && apiErr.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAll returns the shared processor pools of the service instance.
+func (c *SharedProcessorPoolClient) GetAll(cloudInstanceID string) ([]*SharedProcessorPool, error) {
+	result := &struct {
+		SharedProcessorPools []*SharedProcessorPool `json:"sharedProcessorPools"`
+	}{}
+	if err := c.submit("pcloud.sharedprocessorpools.getall", http.MethodGet, "/pcloud/v1/cloud-instances/{cloud_instance_id}/shared-processor-pools",
+		cloudInstanceID, "", nil, result); err != nil {
+		return nil, err
+	}
+	return result.SharedProcessorPools, nil
+}
+
+// Create creates a shared processor pool with the given reserved cores on a host of the host group.
+func (c *SharedProcessorPoolClient) Create(name, hostGroup string, reservedCores int64, cloudInstanceID string) (*SharedProcessorPool, error) {
+	body := &SharedProcessorPool{Name: name, HostGroup: hostGroup, ReservedCores: reservedCores}
+	result := &SharedProcessorPool{}
+	if err := c.submit("pcloud.sharedprocessorpools.post", http.MethodPost, "/pcloud/v1/cloud-instances/{cloud_instance_id}/shared-processor-pools",
+		cloudInstanceID, "", body, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Update changes the reserved cores of a shared processor pool.
+func (c *SharedProcessorPoolClient) Update(id string, reservedCores int64, cloudInstanceID string) (*SharedProcessorPool, error) {
+	body := &SharedProcessorPool{ReservedCores: reservedCores}
+	result := &SharedProcessorPool{}
+	if err := c.submit("pcloud.sharedprocessorpools.put", http.MethodPut, "/pcloud/v1/cloud-instances/{cloud_instance_id}/shared-processor-pools/{shared_processor_pool_id}",
+		cloudInstanceID, id, body, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete deletes a shared processor pool.
+func (c *SharedProcessorPoolClient) Delete(id, cloudInstanceID string) error {
+	return c.submit("pcloud.sharedprocessorpools.delete", http.MethodDelete, "/pcloud/v1/cloud-instances/{cloud_instance_id}/shared-processor-pools/{shared_processor_pool_id}",
+		cloudInstanceID, id, nil, nil)
+}
+
+// CreateInstance creates an instance in the shared processor pool, the pool is set on the instance create request
+// which the power-go-client does not support yet.
+func (c *SharedProcessorPoolClient) CreateInstance(body *models.PVMInstanceCreate, sharedProcessorPoolID, cloudInstanceID string) (*models.PVMInstanceList, error) {
+	request := &struct {
+		*models.PVMInstanceCreate
+		SharedProcessorPool string `json:"sharedProcessorPool"`
+	}{body, sharedProcessorPoolID}
+	result := models.PVMInstanceList{}
+	if err := c.submit("pcloud.pvminstances.post", http.MethodPost, "/pcloud/v1/cloud-instances/{cloud_instance_id}/pvm-instances",
+		cloudInstanceID, "", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *SharedProcessorPoolClient) submit(operationID, method, path, cloudInstanceID, id string, body, result interface{}) error {
+	_, err := c.session.Power.Transport.Submit(&runtime.ClientOperation{
+		ID:                 operationID,
+		Method:             method,
+		PathPattern:        path,
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetTimeout(TIMEOUT); err != nil {
+				return err
+			}
+			if err := r.SetPathParam("cloud_instance_id", cloudInstanceID); err != nil {
+				return err
+			}
+			if id != "" {
+				if err := r.SetPathParam("shared_processor_pool_id", id); err != nil {
+					return err
+				}
+			}
+			if body != nil {
+				return r.SetBodyParam(body)
+			}
+			return nil
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+			if resp.Code() < 200 || resp.Code() >= 300 {
+				message, _ := ioutil.ReadAll(resp.Body())
+				return nil, runtime.NewAPIError(operationID, string(message), resp.Code())
+			}
+			if result == nil || resp.Code() == http.StatusNoContent {
+				return nil, nil
+			}
+			return result, consumer.Consume(resp.Body(), result)
+		}),
+		AuthInfo: ibmpisession.NewAuth(c.session, cloudInstanceID),
+	})
+	return err
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/IBM-Cloud/power-go-client/ibmpisession"
+	"github.com/IBM-Cloud/power-go-client/power/client/p_cloud_volumes"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+const (
+	volumeStateAvailable = "available"
+	volumeStateInUse     = "in-use"
+	volumeStateError     = "error"
+)
+
+// ReconcileVolumes creates the data volumes of the machine and records them in the status.
+// It returns the IDs of the volumes once they are all available to be attached to the instance.
+func (m *PowerVSMachineScope) ReconcileVolumes() ([]string, bool, error) {
+	specs := m.IBMPowerVSMachine.Spec.Volumes
+	if len(specs) == 0 {
+		return nil, true, nil
+	}
+	existing, err := m.getVolumes()
+	if err != nil {
+		return nil, false, err
+	}
+
+	statuses := make([]v1alpha4.PowerVSVolumeStatus, 0, len(specs))
+	ids := make(map[string]string, len(specs))
+	ready := true
+	for _, spec := range specs {
+		name := fmt.Sprintf("%s-%s", m.IBMPowerVSMachine.Name, spec.Name)
+		volume, ok := existing[name]
+		if !ok {
+			body := &models.CreateDataVolume{
+				Name:      &name,
+				Size:      pointer.Float64(float64(spec.Size)),
+				DiskType:  spec.Type,
+				Shareable: &spec.Shareable,
+			}
+			if spec.AffinityPolicy != "" {
+				affinityVolumeID, ok := ids[spec.AffinityVolume]
+				if !ok {
+					return nil, false, fmt.Errorf("affinity volume %q of volume %q must be listed before it", spec.AffinityVolume, spec.Name)
+				}
+				body.AffinityPolicy = &spec.AffinityPolicy
+				body.AffinityVolume = &affinityVolumeID
+			}
+			created, err := m.IBMPowerVSClient.VolumeClient.CreateVolume(&p_cloud_volumes.PcloudCloudinstancesVolumesPostParams{Body: body},
+				m.IBMPowerVSMachine.Spec.ServiceInstanceID, TIMEOUT)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "failed to create volume %s", name)
+			}
+			m.Info("Created volume", "name", name, "id", *created.VolumeID)
+			volume = &models.VolumeReference{VolumeID: created.VolumeID, State: &created.State}
+		}
+
+		state := ""
+		if volume.State != nil {
+			state = *volume.State
+		}
+		switch state {
+		case volumeStateAvailable, volumeStateInUse:
+		case volumeStateError:
+			return nil, false, fmt.Errorf("volume %s is in %s state", name, state)
+		default:
+			ready = false
+		}
+		ids[spec.Name] = *volume.VolumeID
+		statuses = append(statuses, v1alpha4.PowerVSVolumeStatus{Name: spec.Name, ID: *volume.VolumeID, State: state})
+	}
+	m.IBMPowerVSMachine.Status.Volumes = statuses
+
+	volumeIDs := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		volumeIDs = append(volumeIDs, status.ID)
+	}
+	return volumeIDs, ready, nil
+}
+
+// DeleteVolumes deletes the data volumes of the machine which are not retained, once they are detached from the
+// deleted instance. It returns whether all of them are deleted.
+func (m *PowerVSMachineScope) DeleteVolumes() (bool, error) {
+	if len(m.IBMPowerVSMachine.Status.Volumes) == 0 {
+		return true, nil
+	}
+	retain := map[string]bool{}
+	for _, spec := range m.IBMPowerVSMachine.Spec.Volumes {
+		retain[spec.Name] = spec.Retain
+	}
+	existing, err := m.getVolumes()
+	if err != nil {
+		return false, err
+	}
+	byID := make(map[string]*models.VolumeReference, len(existing))
+	for _, volume := range existing {
+		byID[*volume.VolumeID] = volume
+	}
+
+	var remaining []v1alpha4.PowerVSVolumeStatus
+	for _, status := range m.IBMPowerVSMachine.Status.Volumes {
+		volume, ok := byID[status.ID]
+		if !ok || retain[status.Name] {
+			continue
+		}
+		if len(volume.PvmInstanceIds) > 0 {
+			remaining = append(remaining, status)
+			continue
+		}
+		if err := m.IBMPowerVSClient.VolumeClient.DeleteVolume(status.ID, m.IBMPowerVSMachine.Spec.ServiceInstanceID, TIMEOUT); err != nil {
+			return false, errors.Wrapf(err, "failed to delete volume %s", status.ID)
+		}
+		m.Info("Deleted volume", "id", status.ID)
+	}
+	m.IBMPowerVSMachine.Status.Volumes = remaining
+	return len(remaining) == 0, nil
+}
+
+// getVolumes returns the volumes of the service instance by name.
+func (m *PowerVSMachineScope) getVolumes() (map[string]*models.VolumeReference, error) {
+	serviceInstanceID := m.IBMPowerVSMachine.Spec.ServiceInstanceID
+	params := p_cloud_volumes.NewPcloudCloudinstancesVolumesGetallParamsWithTimeout(TIMEOUT).WithCloudInstanceID(serviceInstanceID)
+	resp, err := m.IBMPowerVSClient.session.Power.PCloudVolumes.PcloudCloudinstancesVolumesGetall(params, ibmpisession.NewAuth(m.IBMPowerVSClient.session, serviceInstanceID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list volumes")
+	}
+	if resp == nil || resp.Payload == nil {
+		return nil, fmt.Errorf("failed to list volumes: empty response")
+	}
+	volumes := make(map[string]*models.VolumeReference, len(resp.Payload.Volumes))
+	for _, volume := range resp.Payload.Volumes {
+		volumes[*volume.Name] = volume
+	}
+	return volumes, nil
+}
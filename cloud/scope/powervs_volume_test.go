@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+func newPowerVSMachineScope(t *testing.T, powervs *fakePowerVS, spec v1alpha4.IBMPowerVSMachineSpec) *PowerVSMachineScope {
+	spec.ServiceInstanceID = fakeServiceInstanceID
+	return &PowerVSMachineScope{
+		Logger:           klogr.New(),
+		IBMPowerVSClient: newFakePowerVSClient(t, powervs),
+		IBMPowerVSMachine: &v1alpha4.IBMPowerVSMachine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "machine"},
+			Spec:       spec,
+		},
+	}
+}
+
+func volumes(volumes ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"volumes": volumes}
+}
+
+func volume(id, name, state string, instanceIDs ...string) map[string]interface{} {
+	return map[string]interface{}{"volumeID": id, "name": name, "state": state, "pvmInstanceIds": instanceIDs}
+}
+
+func TestReconcileVolumes(t *testing.T) {
+	tests := []struct {
+		name         string
+		specs        []v1alpha4.PowerVSVolume
+		existing     map[string]interface{}
+		wantIDs      []string
+		wantReady    bool
+		wantErr      string
+		wantStatuses []v1alpha4.PowerVSVolumeStatus
+		wantCreated  []map[string]interface{}
+	}{
+		{
+			name:      "does nothing without volumes",
+			wantReady: true,
+		},
+		{
+			name: "creates the missing volumes with their affinity",
+			specs: []v1alpha4.PowerVSVolume{
+				{Name: "data", Size: 10, Type: "tier1"},
+				{Name: "logs", Size: 20, AffinityPolicy: "affinity", AffinityVolume: "data"},
+			},
+			existing: volumes(volume("data-id", "machine-data", volumeStateAvailable)),
+			wantIDs:  []string{"data-id", "created"},
+			wantStatuses: []v1alpha4.PowerVSVolumeStatus{
+				{Name: "data", ID: "data-id", State: volumeStateAvailable},
+				{Name: "logs", ID: "created", State: "creating"},
+			},
+			wantCreated: []map[string]interface{}{{
+				"name": "machine-logs", "size": float64(20), "shareable": false, "affinityPolicy": "affinity", "affinityVolume": "data-id",
+				"antiAffinityPVMInstances": nil, "antiAffinityVolumes": nil,
+			}},
+		},
+		{
+			name:      "reports the volumes ready once available or in use",
+			specs:     []v1alpha4.PowerVSVolume{{Name: "data", Size: 10}, {Name: "logs", Size: 20}},
+			existing:  volumes(volume("data-id", "machine-data", volumeStateInUse, "instance"), volume("logs-id", "machine-logs", volumeStateAvailable)),
+			wantIDs:   []string{"data-id", "logs-id"},
+			wantReady: true,
+			wantStatuses: []v1alpha4.PowerVSVolumeStatus{
+				{Name: "data", ID: "data-id", State: volumeStateInUse},
+				{Name: "logs", ID: "logs-id", State: volumeStateAvailable},
+			},
+		},
+		{
+			name:     "rejects an affinity volume listed after the volume",
+			specs:    []v1alpha4.PowerVSVolume{{Name: "logs", Size: 20, AffinityPolicy: "affinity", AffinityVolume: "data"}, {Name: "data", Size: 10}},
+			existing: volumes(),
+			wantErr:  `affinity volume "data" of volume "logs" must be listed before it`,
+		},
+		{
+			name:     "fails on a volume in error state",
+			specs:    []v1alpha4.PowerVSVolume{{Name: "data", Size: 10}},
+			existing: volumes(volume("data-id", "machine-data", volumeStateError)),
+			wantErr:  "volume machine-data is in error state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var created []map[string]interface{}
+			powervs := newFakePowerVS()
+			powervs.handle(http.MethodGet, "/volumes", reply(http.StatusOK, tt.existing))
+			powervs.handle(http.MethodPost, "/volumes", func(_ *http.Request, body map[string]interface{}) (int, interface{}) {
+				created = append(created, body)
+				return http.StatusAccepted, map[string]interface{}{"volumeID": "created", "name": body["name"], "state": "creating"}
+			})
+			s := newPowerVSMachineScope(t, powervs, v1alpha4.IBMPowerVSMachineSpec{Volumes: tt.specs})
+
+			ids, ready, err := s.ReconcileVolumes()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(Equal(tt.wantReady))
+			g.Expect(ids).To(Equal(tt.wantIDs))
+			g.Expect(s.IBMPowerVSMachine.Status.Volumes).To(Equal(tt.wantStatuses))
+			g.Expect(created).To(Equal(tt.wantCreated))
+		})
+	}
+}
+
+func TestDeleteVolumes(t *testing.T) {
+	g := NewWithT(t)
+	var deleted []string
+	powervs := newFakePowerVS()
+	powervs.handle(http.MethodGet, "/volumes", reply(http.StatusOK, volumes(
+		volume("data-id", "machine-data", volumeStateAvailable),
+		volume("logs-id", "machine-logs", volumeStateInUse, "instance"),
+		volume("keep-id", "machine-keep", volumeStateAvailable),
+	)))
+	for _, id := range []string{"data-id", "logs-id", "keep-id"} {
+		id := id
+		powervs.handle(http.MethodDelete, "/volumes/"+id, func(*http.Request, map[string]interface{}) (int, interface{}) {
+			deleted = append(deleted, id)
+			return http.StatusOK, map[string]interface{}{}
+		})
+	}
+	s := newPowerVSMachineScope(t, powervs, v1alpha4.IBMPowerVSMachineSpec{Volumes: []v1alpha4.PowerVSVolume{
+		{Name: "data", Size: 10}, {Name: "logs", Size: 10}, {Name: "keep", Size: 10, Retain: true},
+	}})
+	s.IBMPowerVSMachine.Status.Volumes = []v1alpha4.PowerVSVolumeStatus{
+		{Name: "data", ID: "data-id"}, {Name: "logs", ID: "logs-id"}, {Name: "keep", ID: "keep-id"}, {Name: "gone", ID: "gone-id"},
+	}
+
+	done, err := s.DeleteVolumes()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(done).To(BeFalse())
+	g.Expect(deleted).To(Equal([]string{"data-id"}))
+	g.Expect(s.IBMPowerVSMachine.Status.Volumes).To(Equal([]v1alpha4.PowerVSVolumeStatus{{Name: "logs", ID: "logs-id"}}))
+}
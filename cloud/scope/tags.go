@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+const (
+	// TagClusterNamePrefix is the prefix of the tag holding the name of the cluster owning a resource.
+	TagClusterNamePrefix = "capi-cluster-name:"
+	// TagClusterUIDPrefix is the prefix of the tag holding the UID of the cluster owning a resource.
+	TagClusterUIDPrefix = "capi-cluster-uid:"
+	// TagRolePrefix is the prefix of the tag holding the role of a resource in the cluster.
+	TagRolePrefix = "capi-role:"
+
+	// TagRoleCommon is the role of the resources shared by the whole cluster.
+	TagRoleCommon = "common"
+	// TagRoleAPIServer is the role of the resources exposing the API server.
+	TagRoleAPIServer = "apiserver"
+	// TagRoleControlPlane is the role of the control plane machines.
+	TagRoleControlPlane = "control-plane"
+	// TagRoleNode is the role of the worker machines.
+	TagRoleNode = "node"
+)
+
+// clusterTags returns the user tags identifying a resource of the cluster with the given role,
+// followed by the additional user-defined tags.
+func clusterTags(cluster *clusterv1.Cluster, role string, additionalTags ...[]string) []string {
+	tags := []string{
+		TagClusterNamePrefix + cluster.Name,
+		TagClusterUIDPrefix + string(cluster.UID),
+		TagRolePrefix + role,
+	}
+	for _, t := range additionalTags {
+		tags = append(tags, t...)
+	}
+	return tags
+}
+
+// machineRole returns the tag role of a machine.
+func machineRole(machine *clusterv1.Machine) string {
+	if machine != nil {
+		if _, ok := machine.Labels[clusterv1.MachineControlPlaneLabelName]; ok {
+			return TagRoleControlPlane
+		}
+	}
+	return TagRoleNode
+}
+
+// tagsHash returns a hash of the tags desired on the resources identified by ids. The tagging calls are skipped while
+// it matches the hash recorded in the status once the tags were last attached.
+func tagsHash(ids, userTags, accessTags []string) (string, error) {
+	data, err := json.Marshal(struct {
+		IDs        []string `json:"ids"`
+		UserTags   []string `json:"userTags"`
+		AccessTags []string `json:"accessTags"`
+	}{
+		IDs:        ids,
+		UserTags:   userTags,
+		AccessTags: accessTags,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// reconcileTags attaches the tags missing from the resource identified by crn.
+// It never detaches tags: the ones attached by other parties and the ones no longer desired are left untouched.
+func reconcileTags(client *pkg.GlobalTaggingClient, crn string, userTags, accessTags []string) error {
+	if err := reconcileTagsOfType(client, crn, userTags, pkg.TagTypeUser); err != nil {
+		return err
+	}
+	return reconcileTagsOfType(client, crn, accessTags, pkg.TagTypeAccess)
+}
+
+func reconcileTagsOfType(client *pkg.GlobalTaggingClient, crn string, tags []string, tagType string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	attached, err := client.GetTags(crn, tagType)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s tags of resource %s", tagType, crn)
+	}
+	// Tag names are stored in lowercase by the Global Tagging service.
+	attachedSet := make(map[string]bool, len(attached))
+	for _, tag := range attached {
+		attachedSet[strings.ToLower(tag)] = true
+	}
+	var missing []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !attachedSet[tag] {
+			missing = append(missing, tag)
+			attachedSet[tag] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if err := client.AttachTags(crn, missing, tagType); err != nil {
+		return errors.Wrapf(err, "failed to attach %s tags to resource %s", tagType, crn)
+	}
+	return nil
+}
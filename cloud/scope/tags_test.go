@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+// fakeTagging is an in-memory stand-in of the Global Tagging API, tags are keyed by tag type and resource CRN.
+type fakeTagging struct {
+	mu       sync.Mutex
+	tags     map[string]map[string][]string
+	attached [][]string
+	requests int
+}
+
+func (f *fakeTagging) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	w.Header().Set("Content-Type", "application/json")
+	tagType := r.URL.Query().Get("tag_type")
+	if f.tags[tagType] == nil {
+		f.tags[tagType] = map[string][]string{}
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v3/tags":
+		items := []map[string]string{}
+		for _, tag := range f.tags[tagType][r.URL.Query().Get("attached_to")] {
+			items = append(items, map[string]string{"name": tag})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	case r.Method == http.MethodPost && r.URL.Path == "/v3/tags/attach":
+		body := struct {
+			Resources []struct {
+				ResourceID string `json:"resource_id"`
+			} `json:"resources"`
+			TagNames []string `json:"tag_names"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		results := []map[string]interface{}{}
+		for _, resource := range body.Resources {
+			f.tags[tagType][resource.ResourceID] = append(f.tags[tagType][resource.ResourceID], body.TagNames...)
+			results = append(results, map[string]interface{}{"resource_id": resource.ResourceID, "is_error": false})
+		}
+		f.attached = append(f.attached, body.TagNames)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeTaggingClient(t *testing.T, tagging *fakeTagging) *pkg.GlobalTaggingClient {
+	server := httptest.NewServer(tagging)
+	t.Cleanup(server.Close)
+	client, err := pkg.NewGlobalTaggingClient(&core.NoAuthAuthenticator{}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestReconcileTags(t *testing.T) {
+	const crn = "crn:v1:bluemix:public:is:us-south-1:a/account::instance:0717-instance"
+
+	tests := []struct {
+		name         string
+		attached     []string
+		userTags     []string
+		wantTags     []string
+		wantAttached [][]string
+	}{
+		{
+			name:         "attaches the missing tags in lowercase",
+			userTags:     []string{"Env:Prod", " team:a "},
+			wantTags:     []string{"env:prod", "team:a"},
+			wantAttached: [][]string{{"env:prod", "team:a"}},
+		},
+		{
+			name:     "leaves attached tags untouched",
+			attached: []string{"env:prod", "owner:someone"},
+			userTags: []string{"env:prod"},
+			wantTags: []string{"env:prod", "owner:someone"},
+		},
+		{
+			name:     "does not detach tags removed from the spec",
+			attached: []string{"env:prod", "team:a"},
+			userTags: []string{"team:a"},
+			wantTags: []string{"env:prod", "team:a"},
+		},
+		{
+			name:         "re-attaches the detached tags",
+			attached:     []string{"team:a"},
+			userTags:     []string{"env:prod", "team:a", "env:prod"},
+			wantTags:     []string{"env:prod", "team:a"},
+			wantAttached: [][]string{{"env:prod"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tagging := &fakeTagging{tags: map[string]map[string][]string{
+				pkg.TagTypeUser: {crn: tt.attached},
+			}}
+			client := newFakeTaggingClient(t, tagging)
+
+			g.Expect(reconcileTags(client, crn, tt.userTags, nil)).To(Succeed())
+			tags := append([]string{}, tagging.tags[pkg.TagTypeUser][crn]...)
+			sort.Strings(tags)
+			g.Expect(tags).To(Equal(tt.wantTags))
+			g.Expect(tagging.attached).To(Equal(tt.wantAttached))
+		})
+	}
+}
+
+func TestMachineScopeReconcileTags(t *testing.T) {
+	g := NewWithT(t)
+	tagging := &fakeTagging{tags: map[string]map[string][]string{}}
+
+	scope := &MachineScope{
+		IBMVPCClients: IBMVPCClients{TaggingClient: newFakeTaggingClient(t, tagging)},
+		Cluster:       &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "capi", UID: "uid"}},
+		Machine:       &clusterv1.Machine{},
+		IBMVPCMachine: &infrav1.IBMVPCMachine{Spec: infrav1.IBMVPCMachineSpec{
+			Tags:       []string{"team:a"},
+			AccessTags: []string{"project:a"},
+		}},
+	}
+	instance := &vpcv1.Instance{CRN: core.StringPtr("crn:instance")}
+
+	// The machine is reconciled before its IBMVPCCluster is available.
+	g.Expect(scope.ReconcileTags(instance)).To(Succeed())
+	g.Expect(tagging.tags[pkg.TagTypeUser]["crn:instance"]).To(ConsistOf("capi-cluster-name:capi", "capi-cluster-uid:uid", "capi-role:node", "team:a"))
+	g.Expect(tagging.tags[pkg.TagTypeAccess]["crn:instance"]).To(ConsistOf("project:a"))
+
+	// The tags are not reconciled again until they change.
+	requests := tagging.requests
+	g.Expect(scope.ReconcileTags(instance)).To(Succeed())
+	g.Expect(tagging.requests).To(Equal(requests))
+
+	scope.IBMVPCMachine.Spec.Tags = append(scope.IBMVPCMachine.Spec.Tags, "env:prod")
+	g.Expect(scope.ReconcileTags(instance)).To(Succeed())
+	g.Expect(tagging.requests).To(BeNumerically(">", requests))
+	g.Expect(tagging.tags[pkg.TagTypeUser]["crn:instance"]).To(ContainElement("env:prod"))
+
+	// Attaching a volume tags it.
+	requests = tagging.requests
+	instance.VolumeAttachments = []vpcv1.VolumeAttachmentReferenceInstanceContext{{Volume: &vpcv1.VolumeReference{CRN: core.StringPtr("crn:volume")}}}
+	g.Expect(scope.ReconcileTags(instance)).To(Succeed())
+	g.Expect(tagging.requests).To(BeNumerically(">", requests))
+	g.Expect(tagging.tags[pkg.TagTypeUser]["crn:volume"]).To(ContainElement("team:a"))
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+const (
+	// cloudConfigContentType is the MIME type of cloud-config parts.
+	cloudConfigContentType = "text/cloud-config"
+	// jinjaContentType is the MIME type of parts rendered as jinja templates, like the kubeadm bootstrap data.
+	jinjaContentType = "text/jinja2"
+
+	// cloudConfigMergeType appends the lists and merges the dictionaries of the cloud-config parts, so the
+	// write_files and runcmd of a part do not replace the ones of the previous parts.
+	cloudConfigMergeType = "list(append)+dict(no_replace,recurse_list)+str()"
+)
+
+// userDataContentTypes maps the first line of a cloud-init part to its MIME type.
+var userDataContentTypes = []struct {
+	prefix      string
+	contentType string
+}{
+	{"#cloud-config", cloudConfigContentType},
+	{"## template: jinja", jinjaContentType},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{"#!", "text/x-shellscript"},
+}
+
+// userDataPart is a part of a multi-part MIME cloud-init user data.
+type userDataPart struct {
+	contentType string
+	data        []byte
+}
+
+// getAdditionalUserData returns the cloud-init parts of the referenced Secrets in the order they are listed.
+func getAdditionalUserData(c client.Client, namespace string, refs []v1alpha4.UserDataSecretReference) ([]userDataPart, error) {
+	parts := make([]userDataPart, 0, len(refs))
+	for _, ref := range refs {
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve user data secret %s/%s", namespace, ref.Name)
+		}
+		key := ref.Key
+		if key == "" {
+			key = "value"
+		}
+		data, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("user data secret %s/%s has no key %s", namespace, ref.Name, key)
+		}
+		contentType := ref.ContentType
+		if contentType == "" {
+			contentType = detectUserDataContentType(data)
+			if contentType == "" {
+				return nil, fmt.Errorf("failed to detect the content type of user data secret %s/%s, set contentType", namespace, ref.Name)
+			}
+		}
+		parts = append(parts, userDataPart{contentType: contentType, data: data})
+	}
+	return parts, nil
+}
+
+// detectUserDataContentType returns the MIME type of a cloud-init part from its first line, or an empty string.
+func detectUserDataContentType(data []byte) string {
+	for _, t := range userDataContentTypes {
+		if bytes.HasPrefix(data, []byte(t.prefix)) {
+			return t.contentType
+		}
+	}
+	return ""
+}
+
+// mergeUserData returns a multi-part MIME cloud-init user data with the additional parts in the order they are listed,
+// followed by the bootstrap data so its commands run after the ones of the additional parts.
+func mergeUserData(bootstrapData []byte, parts []userDataPart) ([]byte, error) {
+	bootstrapContentType := detectUserDataContentType(bootstrapData)
+	if bootstrapContentType == "" {
+		bootstrapContentType = cloudConfigContentType
+	}
+	parts = append(parts, userDataPart{contentType: bootstrapContentType, data: bootstrapData})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for i, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", part.contentType))
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"part-%03d\"", i+1))
+		if part.contentType == cloudConfigContentType || part.contentType == jinjaContentType {
+			header.Set("Merge-Type", cloudConfigMergeType)
+		}
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(part.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	userData := &bytes.Buffer{}
+	fmt.Fprintf(userData, "Content-Type: multipart/mixed; boundary=\"%s\"\r\nMIME-Version: 1.0\r\n\r\n", writer.Boundary())
+	userData.Write(body.Bytes())
+	return userData.Bytes(), nil
+}
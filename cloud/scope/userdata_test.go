@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMergeUserData(t *testing.T) {
+	// userDataMIMEPart is a part of the merged user data as read by cloud-init.
+	type userDataMIMEPart struct {
+		contentType string
+		mergeType   string
+		filename    string
+		data        string
+	}
+
+	tests := []struct {
+		name          string
+		bootstrapData string
+		parts         []userDataPart
+		want          []userDataMIMEPart
+	}{
+		{
+			name:          "bootstrap data only",
+			bootstrapData: "## template: jinja\n#cloud-config\n",
+			want: []userDataMIMEPart{
+				{contentType: `text/jinja2; charset="utf-8"`, mergeType: cloudConfigMergeType, filename: "part-001", data: "## template: jinja\n#cloud-config\n"},
+			},
+		},
+		{
+			name:          "additional parts run before the bootstrap data",
+			bootstrapData: "#cloud-config\nruncmd: [kubeadm join]\n",
+			parts: []userDataPart{
+				{contentType: "text/x-shellscript", data: []byte("#!/bin/sh\necho first\n")},
+				{contentType: cloudConfigContentType, data: []byte("#cloud-config\nruncmd: [echo second]\n")},
+			},
+			want: []userDataMIMEPart{
+				{contentType: `text/x-shellscript; charset="utf-8"`, filename: "part-001", data: "#!/bin/sh\necho first\n"},
+				{contentType: `text/cloud-config; charset="utf-8"`, mergeType: cloudConfigMergeType, filename: "part-002", data: "#cloud-config\nruncmd: [echo second]\n"},
+				{contentType: `text/cloud-config; charset="utf-8"`, mergeType: cloudConfigMergeType, filename: "part-003", data: "#cloud-config\nruncmd: [kubeadm join]\n"},
+			},
+		},
+		{
+			name:          "bootstrap data without a known first line defaults to cloud-config",
+			bootstrapData: "write_files: []\n",
+			parts: []userDataPart{
+				{contentType: "text/cloud-boothook", data: []byte("#cloud-boothook\necho boot\n")},
+			},
+			want: []userDataMIMEPart{
+				{contentType: `text/cloud-boothook; charset="utf-8"`, filename: "part-001", data: "#cloud-boothook\necho boot\n"},
+				{contentType: `text/cloud-config; charset="utf-8"`, mergeType: cloudConfigMergeType, filename: "part-002", data: "write_files: []\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			userData, err := mergeUserData([]byte(tt.bootstrapData), tt.parts)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			message, err := mail.ReadMessage(bytes.NewReader(userData))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(message.Header.Get("MIME-Version")).To(Equal("1.0"))
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(mediaType).To(Equal("multipart/mixed"))
+			boundary := params["boundary"]
+			g.Expect(boundary).NotTo(BeEmpty())
+
+			var got []userDataMIMEPart
+			reader := multipart.NewReader(message.Body, boundary)
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				g.Expect(err).NotTo(HaveOccurred())
+				data, err := ioutil.ReadAll(part)
+				g.Expect(err).NotTo(HaveOccurred())
+				// The boundary must not occur in the content of a part, or the part would be split.
+				g.Expect(string(data)).NotTo(ContainSubstring(boundary))
+				got = append(got, userDataMIMEPart{
+					contentType: part.Header.Get("Content-Type"),
+					mergeType:   part.Header.Get("Merge-Type"),
+					filename:    part.FileName(),
+					data:        string(data),
+				})
+			}
+			g.Expect(got).To(Equal(tt.want))
+
+			// Every user data gets its own boundary.
+			other, err := mergeUserData([]byte(tt.bootstrapData), tt.parts)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(other).NotTo(Equal(userData))
+		})
+	}
+}
@@ -18,17 +18,21 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
@@ -43,6 +47,7 @@ type IBMPowerVSClusterReconciler struct {
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 
 // Reconcile implements controller runtime Reconciler interface and handles reconcileation logic for IBMPowerVSCluster.
 func (r *IBMPowerVSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -75,6 +80,9 @@ func (r *IBMPowerVSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		Cluster:           cluster,
 		IBMPowerVSCluster: ibmCluster,
 	})
+	if err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
 
 	// Always close the scope when exiting this function so we can persist any GCPMachine changes.
 	defer func() {
@@ -88,9 +96,6 @@ func (r *IBMPowerVSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return r.reconcileDelete(clusterScope)
 	}
 
-	if err != nil {
-		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
-	}
 	return r.reconcile(ctx, clusterScope)
 }
 
@@ -100,12 +105,86 @@ func (r *IBMPowerVSClusterReconciler) reconcile(ctx context.Context, clusterScop
 		return ctrl.Result{}, nil
 	}
 
+	ready, err := clusterScope.ReconcileServiceInstance()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile service instance for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if !ready {
+		clusterScope.Info("Waiting for the service instance to be active")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	ready, err = clusterScope.ReconcileNetwork()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile network for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if !ready {
+		clusterScope.Info("Waiting for the network to be ready")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	ready, err = clusterScope.ReconcileControlPlaneVIP()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile control plane virtual IP for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if !ready {
+		clusterScope.Info("Waiting for the control plane virtual IP to be ready")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if err := clusterScope.ReconcilePlacementGroups(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile placement groups for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+
+	if err := clusterScope.ReconcileSharedProcessorPool(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile shared processor pool for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+
+	if err := clusterScope.ReconcileSSHKey(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile SSH key for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+
+	if err := clusterScope.ReconcileBootstrapBucket(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile bootstrap data bucket for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+
 	clusterScope.IBMPowerVSCluster.Status.Ready = true
 
 	return ctrl.Result{}, nil
 }
 
 func (r *IBMPowerVSClusterReconciler) reconcileDelete(clusterScope *scope.PowerVSClusterScope) (ctrl.Result, error) {
+	deleted, err := clusterScope.DeletePlacementGroups()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete placement groups for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if !deleted {
+		clusterScope.Info("Waiting for the placement groups to have no members")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	deleted, err = clusterScope.DeleteSharedProcessorPool()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete shared processor pool for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if !deleted {
+		clusterScope.Info("Waiting for the shared processor pool to have no instances")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	if err := clusterScope.DeleteControlPlaneVIP(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete control plane virtual IP for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if err := clusterScope.DeleteNetwork(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete network for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if err := clusterScope.DeleteSSHKey(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete SSH key for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if err := clusterScope.DeleteBootstrapBucket(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete bootstrap data bucket for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
+	if err := clusterScope.DeleteServiceInstance(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete service instance for IBMPowerVSCluster %s/%s", clusterScope.IBMPowerVSCluster.Namespace, clusterScope.IBMPowerVSCluster.Name)
+	}
 	controllerutil.RemoveFinalizer(clusterScope.IBMPowerVSCluster, v1alpha4.IBMPowerVSClusterFinalizer)
 	return ctrl.Result{}, nil
 }
@@ -114,5 +193,25 @@ func (r *IBMPowerVSClusterReconciler) reconcileDelete(clusterScope *scope.PowerV
 func (r *IBMPowerVSClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha4.IBMPowerVSCluster{}).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.sshKeySecretToIBMPowerVSClusters),
+		).
 		Complete(r)
 }
+
+// sshKeySecretToIBMPowerVSClusters maps a Secret to the IBMPowerVSClusters registering its SSH key.
+func (r *IBMPowerVSClusterReconciler) sshKeySecretToIBMPowerVSClusters(o client.Object) []reconcile.Request {
+	clusters := &v1alpha4.IBMPowerVSClusterList{}
+	if err := r.List(context.TODO(), clusters, client.InNamespace(o.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list IBMPowerVSClusters", "namespace", o.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		if cluster.Spec.SSHKeySecret != nil && cluster.Spec.SSHKeySecret.Name == o.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+		}
+	}
+	return requests
+}
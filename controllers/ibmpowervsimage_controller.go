@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
+)
+
+// IBMPowerVSImageReconciler reconciles a IBMPowerVSImage object
+type IBMPowerVSImageReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsimages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsimages/status,verbs=get;update;patch
+
+// Reconcile implements controller runtime Reconciler interface and handles reconcileation logic for IBMPowerVSImage.
+func (r *IBMPowerVSImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := r.Log.WithValues("ibmpowervsimage", req.NamespacedName)
+
+	// Fetch the IBMPowerVSImage instance.
+	ibmImage := &v1alpha4.IBMPowerVSImage{}
+	err := r.Get(ctx, req.NamespacedName, ibmImage)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	serviceInstanceID, cluster, err := r.getServiceInstanceID(ctx, ibmImage)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if serviceInstanceID == "" {
+		if !ibmImage.DeletionTimestamp.IsZero() {
+			// The service instance is gone with the cluster, and the image with it.
+			return ctrl.Result{}, r.removeFinalizer(ctx, ibmImage)
+		}
+		log.Info("Waiting for the service instance of the image")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Create the scope.
+	imageScope, err := scope.NewPowerVSImageScope(scope.PowerVSImageScopeParams{
+		Client:            r.Client,
+		Logger:            log,
+		IBMPowerVSImage:   ibmImage,
+		ServiceInstanceID: serviceInstanceID,
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any IBMPowerVSImage changes.
+	defer func() {
+		if err := imageScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted images
+	if !ibmImage.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(imageScope)
+	}
+
+	if cluster != nil {
+		ibmImage.OwnerReferences = util.EnsureOwnerRef(ibmImage.OwnerReferences, metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+		})
+	}
+	return r.reconcile(imageScope)
+}
+
+func (r *IBMPowerVSImageReconciler) reconcile(imageScope *scope.PowerVSImageScope) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(imageScope.IBMPowerVSImage, v1alpha4.IBMPowerVSImageFinalizer) {
+		controllerutil.AddFinalizer(imageScope.IBMPowerVSImage, v1alpha4.IBMPowerVSImageFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	done, err := imageScope.ReconcileImage()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile IBMPowerVSImage %s/%s", imageScope.IBMPowerVSImage.Namespace, imageScope.IBMPowerVSImage.Name)
+	}
+	if !done {
+		imageScope.Info("Waiting for the image to be imported")
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *IBMPowerVSImageReconciler) reconcileDelete(imageScope *scope.PowerVSImageScope) (ctrl.Result, error) {
+	if err := imageScope.DeleteImage(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete IBMPowerVSImage %s/%s", imageScope.IBMPowerVSImage.Namespace, imageScope.IBMPowerVSImage.Name)
+	}
+	controllerutil.RemoveFinalizer(imageScope.IBMPowerVSImage, v1alpha4.IBMPowerVSImageFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// getServiceInstanceID returns the service instance of the image, either set in the spec or the one of the
+// IBMPowerVSCluster of its cluster, and the cluster when the image belongs to one.
+func (r *IBMPowerVSImageReconciler) getServiceInstanceID(ctx context.Context, ibmImage *v1alpha4.IBMPowerVSImage) (string, *clusterv1.Cluster, error) {
+	if ibmImage.Spec.ClusterName == "" {
+		return ibmImage.Spec.ServiceInstanceID, nil, nil
+	}
+
+	cluster, err := util.GetClusterByName(ctx, r.Client, ibmImage.Namespace, ibmImage.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ibmImage.Spec.ServiceInstanceID, nil, nil
+		}
+		return "", nil, err
+	}
+	if ibmImage.Spec.ServiceInstanceID != "" || cluster.Spec.InfrastructureRef == nil {
+		return ibmImage.Spec.ServiceInstanceID, cluster, nil
+	}
+
+	ibmCluster := &v1alpha4.IBMPowerVSCluster{}
+	key := client.ObjectKey{Namespace: ibmImage.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := r.Client.Get(ctx, key, ibmCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", cluster, nil
+		}
+		return "", nil, err
+	}
+	return ibmCluster.GetServiceInstanceID(), cluster, nil
+}
+
+func (r *IBMPowerVSImageReconciler) removeFinalizer(ctx context.Context, ibmImage *v1alpha4.IBMPowerVSImage) error {
+	helper, err := patch.NewHelper(ibmImage, r.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+	controllerutil.RemoveFinalizer(ibmImage, v1alpha4.IBMPowerVSImageFinalizer)
+	return helper.Patch(ctx, ibmImage)
+}
+
+// SetupWithManager creates a new IBMPowerVSImage controller for a manager.
+func (r *IBMPowerVSImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha4.IBMPowerVSImage{}).
+		Complete(r)
+}
@@ -26,14 +26,16 @@ import (
 
 	"github.com/IBM-Cloud/power-go-client/power/models"
 
-	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
@@ -42,12 +44,16 @@ import (
 // IBMPowerVSMachineReconciler reconciles a IBMPowerVSMachine object
 type IBMPowerVSMachineReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	// APIReader reads the IBMPowerVSIPPools the addresses of the machines are claimed from, bypassing the cache.
+	APIReader client.Reader
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsippools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmpowervsippools/status,verbs=get;update;patch
 
 // Reconcile implements controller runtime Reconciler interface and handles reconcileation logic for IBMPowerVSMachine.
 func (r *IBMPowerVSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -90,10 +96,15 @@ func (r *IBMPowerVSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		log.Info("IBMPowerVSCluster is not available yet")
 		return ctrl.Result{}, nil
 	}
+	if ibmPowerVSMachine.Spec.ServiceInstanceID == "" && ibmCluster.GetServiceInstanceID() == "" {
+		log.Info("Waiting for the service instance of the IBMPowerVSCluster")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
 
 	// Create the machine scope
 	machineScope, err := scope.NewPowerVSMachineScope(scope.PowerVSMachineScopeParams{
 		Client:            r.Client,
+		APIReader:         r.APIReader,
 		Logger:            log,
 		Cluster:           cluster,
 		IBMPowerVSCluster: ibmCluster,
@@ -124,28 +135,50 @@ func (r *IBMPowerVSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Re
 func (r *IBMPowerVSMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha4.IBMPowerVSMachine{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(v1alpha4.GroupVersion.WithKind("IBMPowerVSMachine"))),
+		).
 		Complete(r)
 }
 
 func (r *IBMPowerVSMachineReconciler) reconcileDelete(scope *scope.PowerVSMachineScope) (_ ctrl.Result, reterr error) {
 	scope.Info("Handling deleted IBMPowerVSMachine")
 
-	defer func() {
-		if reterr == nil {
-			// VSI is deleted so remove the finalizer.
-			controllerutil.RemoveFinalizer(scope.IBMPowerVSMachine, v1alpha4.IBMPowerVSMachineFinalizer)
-		}
-	}()
+	if err := scope.DeleteBootstrapData(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete bootstrap data for IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
+	}
 
+	// The instance may hold the claimed addresses even though its id was not recorded.
+	if err := scope.ReconcileInstanceID(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to look up the instance of IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
+	}
 	if scope.IBMPowerVSMachine.Status.InstanceID == "" {
 		scope.Info("InstanceID is not yet set, hence not invoking the powervs API to delete the instance")
-		return ctrl.Result{}, nil
+	} else {
+		if err := scope.DeleteMachine(); err != nil {
+			scope.Info("error deleting IBMPowerVSMachine")
+			return ctrl.Result{}, errors.Wrapf(err, "error deleting IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
+		}
+		scope.IBMPowerVSMachine.Status.InstanceID = ""
 	}
-	if err := scope.DeleteMachine(); err != nil {
-		scope.Info("error deleting IBMPowerVSMachine")
-		return ctrl.Result{}, errors.Wrapf(err, "error deleting IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
+
+	// The volumes are detached once the instance is deleted.
+	deleted, err := scope.DeleteVolumes()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete volumes for IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
+	}
+	if !deleted {
+		scope.Info("Waiting for the volumes to be detached")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if err := scope.ReleaseAddresses(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to release addresses for IBMPowerVSMachine %s/%s", scope.IBMPowerVSMachine.Namespace, scope.IBMPowerVSMachine.Name)
 	}
 
+	// VSI is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(scope.IBMPowerVSMachine, v1alpha4.IBMPowerVSMachineFinalizer)
 	return ctrl.Result{}, nil
 }
 
@@ -163,41 +196,77 @@ func (r *IBMPowerVSMachineReconciler) reconcileNormal(ctx context.Context, machi
 		return ctrl.Result{}, nil
 	}
 
+	// Make sure the referenced image is imported.
+	if machineScope.IBMPowerVSMachine.Status.InstanceID == "" && machineScope.IBMPowerVSMachine.Spec.ImageRef != nil {
+		image, err := machineScope.GetImage()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !image.Status.Ready {
+			machineScope.Info("Waiting for the IBMPowerVSImage to be ready", "image", image.Name)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+
 	ins, err := r.getOrCreate(machineScope)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile VSI for IBMPowerVSMachine %s/%s", machineScope.IBMPowerVSMachine.Namespace, machineScope.IBMPowerVSMachine.Name)
 	}
 
+	resized := true
 	if ins != nil {
-		instance, err := machineScope.IBMPowerVSClient.InstanceClient.Get(*ins.PvmInstanceID, machineScope.IBMPowerVSMachine.Spec.ServiceInstanceID, 60*time.Minute)
+		instance, err := machineScope.GetMachine(*ins.PvmInstanceID)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		machineScope.IBMPowerVSMachine.Status.InstanceID = *instance.PvmInstanceID
-		var addresses []v1.NodeAddress
-		for _, network := range instance.Networks {
-			addresses = append(addresses, v1.NodeAddress{
-				Type:    v1.NodeInternalIP,
-				Address: network.IPAddress,
-			})
-			if network.ExternalIP != "" {
-				addresses = append(addresses, v1.NodeAddress{
-					Type:    v1.NodeExternalIP,
-					Address: network.ExternalIP,
-				})
-			}
-		}
-		machineScope.IBMPowerVSMachine.Status.Addresses = addresses
+		machineScope.SetAddresses(instance)
 		if instance.Health != nil {
 			machineScope.IBMPowerVSMachine.Status.Health = instance.Health.Status
 		}
 		machineScope.IBMPowerVSMachine.Status.InstanceState = *instance.Status
-		if machineScope.IBMPowerVSMachine.Status.InstanceState == "ACTIVE" {
+		if instance.StorageType != nil {
+			machineScope.IBMPowerVSMachine.Status.StorageType = *instance.StorageType
+		}
+		machineScope.IBMPowerVSMachine.Status.StoragePool = instance.StoragePool
+		switch machineScope.IBMPowerVSMachine.Status.InstanceState {
+		case "ACTIVE":
 			machineScope.IBMPowerVSMachine.Status.Ready = true
+		case "ERROR":
+			machineScope.IBMPowerVSMachine.Status.Ready = false
+			if instance.Fault != nil {
+				machineScope.IBMPowerVSMachine.Status.Fault = instance.Fault.Message
+			}
+		}
+		// The instance has fetched its bootstrap data once its node joined the cluster.
+		if machineScope.IBMPowerVSMachine.Status.Ready && machineScope.Machine.Status.NodeRef != nil {
+			if err := machineScope.DeleteBootstrapData(); err != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to delete bootstrap data for IBMPowerVSMachine %s/%s", machineScope.IBMPowerVSMachine.Namespace, machineScope.IBMPowerVSMachine.Name)
+			}
+		}
+		if err := machineScope.ReconcileTags(*instance.PvmInstanceID); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile tags for IBMPowerVSMachine %s/%s", machineScope.IBMPowerVSMachine.Namespace, machineScope.IBMPowerVSMachine.Name)
+		}
+		if resized, err = machineScope.ReconcileResize(instance); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to resize IBMPowerVSMachine %s/%s", machineScope.IBMPowerVSMachine.Namespace, machineScope.IBMPowerVSMachine.Name)
 		}
 		machineScope.Info(*ins.PvmInstanceID)
 	}
 	machineScope.IBMPowerVSMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("ibmpowervs://%s/%s", machineScope.Machine.Spec.ClusterName, machineScope.IBMPowerVSMachine.Name))
 
-	return ctrl.Result{}, nil
+	if ins == nil {
+		// The instance waits for its volumes.
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	switch machineScope.IBMPowerVSMachine.Status.InstanceState {
+	case "ACTIVE", "ERROR":
+		if !resized {
+			machineScope.Info("Waiting for the instance to be resized")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return ctrl.Result{}, nil
+	default:
+		machineScope.Info("Waiting for the instance to become active", "state", machineScope.IBMPowerVSMachine.Status.InstanceState)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
 }
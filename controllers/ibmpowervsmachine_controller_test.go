@@ -35,8 +35,9 @@ var _ = Describe("IBMPowerVSMachineReconciler", func() {
 	Context("Reconcile an IBMPowerVSMachine", func() {
 		It("should not error or requeue the request", func() {
 			reconciler := &IBMPowerVSMachineReconciler{
-				Client: k8sClient,
-				Log:    klogr.New(),
+				Client:    k8sClient,
+				APIReader: k8sClient,
+				Log:       klogr.New(),
 			}
 			By("Calling reconcile")
 			ctx := context.Background()
@@ -117,6 +117,16 @@ func (r *IBMVPCClusterReconciler) reconcile(ctx context.Context, clusterScope *s
 		return ctrl.Result{}, nil
 	}
 
+	if err := clusterScope.ValidateTrustedProfile(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to validate trusted profile for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
+	}
+
+	if clusterScope.IBMVPCCluster.Status.ResourceGroupID == "" {
+		if err := clusterScope.ReconcileResourceGroup(); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to resolve resource group for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
+		}
+	}
+
 	vpc, err := clusterScope.CreateVPC()
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile VPC for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
@@ -162,6 +172,24 @@ func (r *IBMVPCClusterReconciler) reconcile(ctx context.Context, clusterScope *s
 		}
 	}
 
+	if clusterScope.IBMVPCCluster.Spec.ControlPlanePlacementGroup != nil && clusterScope.IBMVPCCluster.Status.ControlPlanePlacementGroupID == "" {
+		placementGroup, err := clusterScope.CreateControlPlanePlacementGroup()
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile control plane placement group for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
+		}
+		if placementGroup != nil {
+			clusterScope.IBMVPCCluster.Status.ControlPlanePlacementGroupID = *placementGroup.ID
+		}
+	}
+
+	if err := clusterScope.ReconcileBootstrapBucket(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile bootstrap data bucket for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
+	}
+
+	if err := clusterScope.ReconcileTags(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile tags for IBMVPCCluster %s/%s", clusterScope.IBMVPCCluster.Namespace, clusterScope.IBMVPCCluster.Name)
+	}
+
 	clusterScope.IBMVPCCluster.Status.Ready = true
 	return ctrl.Result{}, nil
 }
@@ -188,6 +216,14 @@ func (r *IBMVPCClusterReconciler) reconcileDelete(clusterScope *scope.ClusterSco
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete floatingIP")
 	}
 
+	if err := clusterScope.DeleteControlPlanePlacementGroup(); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete control plane placement group")
+	}
+
+	if err := clusterScope.DeleteBootstrapBucket(); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete bootstrap data bucket")
+	}
+
 	if err := clusterScope.DeleteVPC(); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete VPC")
 	}
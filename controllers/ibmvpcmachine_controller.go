@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -32,9 +33,12 @@ import (
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrastructurev1alpha3 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
@@ -138,6 +142,10 @@ func (r *IBMVPCMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 func (r *IBMVPCMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&infrastructurev1alpha3.IBMVPCMachine{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(infrastructurev1alpha3.GroupVersion.WithKind("IBMVPCMachine"))),
+		).
 		Complete(r)
 }
 
@@ -150,9 +158,28 @@ func (r *IBMVPCMachineReconciler) reconcileNormal(ctx context.Context, machineSc
 		return ctrl.Result{}, nil
 	}
 
-	if machineScope.IBMVPCCluster.Status.Subnet.ID != nil {
-		machineScope.IBMVPCMachine.Spec.PrimaryNetworkInterface = infrastructurev1alpha3.NetworkInterface{
-			Subnet: *machineScope.IBMVPCCluster.Status.Subnet.ID,
+	networkInterface := machineScope.IBMVPCMachine.Spec.PrimaryNetworkInterface
+	if networkInterface.Subnet == "" && networkInterface.SubnetRef == nil && machineScope.IBMVPCCluster.Status.Subnet.ID != nil {
+		machineScope.IBMVPCMachine.Spec.PrimaryNetworkInterface.Subnet = *machineScope.IBMVPCCluster.Status.Subnet.ID
+	}
+
+	if machineScope.IBMVPCMachine.Status.InstanceID == "" {
+		valid, err := machineScope.ValidateProfile()
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to validate profile for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
+		}
+		if !valid {
+			machineScope.Info("Instance profile not found in the region", "profile", machineScope.IBMVPCMachine.Spec.Profile)
+			return ctrl.Result{}, nil
+		}
+
+		valid, err = machineScope.ValidateTrustedProfile()
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to validate trusted profile for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
+		}
+		if !valid {
+			machineScope.Info("Trusted profile not found")
+			return ctrl.Result{}, nil
 		}
 	}
 
@@ -160,40 +187,70 @@ func (r *IBMVPCMachineReconciler) reconcileNormal(ctx context.Context, machineSc
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile VSI for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
 	}
+	if instance == nil {
+		// The instance recorded in the status is gone, it is not recreated as the machine would be replaced.
+		machineScope.IBMVPCMachine.Status.Ready = false
+		conditions.MarkFalse(machineScope.IBMVPCMachine, infrastructurev1alpha3.InstanceReadyCondition, infrastructurev1alpha3.InstanceNotFoundReason, clusterv1.ConditionSeverityError,
+			"instance %s not found", machineScope.IBMVPCMachine.Status.InstanceID)
+		return ctrl.Result{}, nil
+	}
+
+	machineScope.IBMVPCMachine.Status.InstanceID = *instance.ID
+	machineScope.IBMVPCMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("ibmvpc://%s/%s", machineScope.Machine.Spec.ClusterName, machineScope.IBMVPCMachine.Name))
+	machineScope.SetInstanceState(instance)
+	machineScope.IBMVPCMachine.Status.Addresses = machineScope.GetAddresses(instance)
+	machineScope.IBMVPCMachine.Status.DataVolumes = machineScope.GetDataVolumesStatus(instance)
+	if instance.DedicatedHost != nil {
+		machineScope.IBMVPCMachine.Status.DedicatedHostID = *instance.DedicatedHost.ID
+	}
+
+	switch *instance.Status {
+	case vpcv1.InstanceStatusPendingConst, vpcv1.InstanceStatusStartingConst, vpcv1.InstanceStatusRestartingConst, vpcv1.InstanceStatusResumingConst:
+		machineScope.Info("Instance is not running yet", "state", *instance.Status)
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	case vpcv1.InstanceStatusRunningConst:
+	default:
+		machineScope.Info("Instance is not running", "state", *instance.Status)
+		return ctrl.Result{}, nil
+	}
 
-	if instance != nil {
-		machineScope.IBMVPCMachine.Status.InstanceID = *instance.ID
-		machineScope.IBMVPCMachine.Status.Addresses = []v1.NodeAddress{
-			v1.NodeAddress{
-				Type:    v1.NodeInternalIP,
-				Address: *instance.PrimaryNetworkInterface.PrimaryIpv4Address,
-			},
+	// The instance has fetched its bootstrap data once its node joined the cluster.
+	if machineScope.Machine.Status.NodeRef != nil {
+		if err := machineScope.DeleteBootstrapData(); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete bootstrap data for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
 		}
-		_, ok := machineScope.IBMVPCMachine.Labels[clusterv1.MachineControlPlaneLabelName]
-		machineScope.IBMVPCMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("ibmvpc://%s/%s", machineScope.Machine.Spec.ClusterName, machineScope.IBMVPCMachine.Name))
-		if ok {
-			options := &vpcv1.AddInstanceNetworkInterfaceFloatingIPOptions{}
-			options.SetID(*machineScope.IBMVPCCluster.Status.APIEndpoint.FIPID)
-			options.SetInstanceID(*instance.ID)
-			options.SetNetworkInterfaceID(*instance.PrimaryNetworkInterface.ID)
-			floatingIP, _, err :=
-				machineScope.IBMVPCClients.VPCService.AddInstanceNetworkInterfaceFloatingIP(options)
-			if err != nil {
-				return ctrl.Result{}, errors.Wrapf(err, "failed to bind floating IP to control plane %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
-			}
-			machineScope.IBMVPCMachine.Status.Addresses = append(machineScope.IBMVPCMachine.Status.Addresses, v1.NodeAddress{
-				Type:    v1.NodeExternalIP,
-				Address: *floatingIP.Address,
-			})
+	}
+
+	if err := machineScope.ReconcilePlacement(instance); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile placement for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
+	}
+	if err := machineScope.ReconcileTags(instance); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile tags for IBMVPCMachine %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
+	}
+	_, ok := machineScope.IBMVPCMachine.Labels[clusterv1.MachineControlPlaneLabelName]
+	if ok {
+		options := &vpcv1.AddInstanceNetworkInterfaceFloatingIPOptions{}
+		options.SetID(*machineScope.IBMVPCCluster.Status.APIEndpoint.FIPID)
+		options.SetInstanceID(*instance.ID)
+		options.SetNetworkInterfaceID(*instance.PrimaryNetworkInterface.ID)
+		floatingIP, _, err :=
+			machineScope.IBMVPCClients.VPCService.AddInstanceNetworkInterfaceFloatingIP(options)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to bind floating IP to control plane %s/%s", machineScope.IBMVPCMachine.Namespace, machineScope.IBMVPCMachine.Name)
 		}
-		machineScope.IBMVPCMachine.Status.Ready = true
-		machineScope.Info(*instance.ID)
+		machineScope.IBMVPCMachine.Status.Addresses = append(machineScope.IBMVPCMachine.Status.Addresses, v1.NodeAddress{
+			Type:    v1.NodeExternalIP,
+			Address: *floatingIP.Address,
+		})
 	}
 
 	return ctrl.Result{}, nil
 }
 
 func (r *IBMVPCMachineReconciler) getOrCreate(scope *scope.MachineScope) (*vpcv1.Instance, error) {
+	if scope.IBMVPCMachine.Status.InstanceID != "" {
+		return scope.GetMachine(scope.IBMVPCMachine.Status.InstanceID)
+	}
 	instance, err := scope.CreateMachine()
 	return instance, err
 }
@@ -201,6 +258,10 @@ func (r *IBMVPCMachineReconciler) getOrCreate(scope *scope.MachineScope) (*vpcv1
 func (r *IBMVPCMachineReconciler) reconcileDelete(scope *scope.MachineScope) (_ ctrl.Result, reterr error) {
 	scope.Info("Handling deleted IBMVPCMachine")
 
+	if err := scope.DeleteBootstrapData(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete bootstrap data for IBMVPCMachine %s/%s", scope.IBMVPCMachine.Namespace, scope.IBMVPCMachine.Name)
+	}
+
 	if err := scope.DeleteMachine(); err != nil {
 		scope.Info("error deleting IBMVPCMachine")
 		return ctrl.Result{}, errors.Wrapf(err, "error deleting IBMVPCMachine %s/%s", scope.IBMVPCMachine.Namespace, scope.IBMVPCMachine.Spec.Name)
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1alpha3 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+// IBMVPCMachineTemplateReconciler reconciles a IBMVPCMachineTemplate object
+type IBMVPCMachineTemplateReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmvpcmachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmvpcmachinetemplates/status,verbs=get;update;patch
+
+// Reconcile implements controller runtime Reconciler interface and publishes the capacity of the instance profile of an IBMVPCMachineTemplate.
+func (r *IBMVPCMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := r.Log.WithValues("ibmvpcmachinetemplate", req.NamespacedName)
+
+	// Fetch the IBMVPCMachineTemplate instance.
+	ibmVpcMachineTemplate := &infrastructurev1alpha3.IBMVPCMachineTemplate{}
+	err := r.Get(ctx, req.NamespacedName, ibmVpcMachineTemplate)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !ibmVpcMachineTemplate.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	svcEndpoint := os.Getenv("SERVICE_ENDPOINT")
+
+	authenticator, err := pkg.GetAuthenticator()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get authenticator")
+	}
+
+	// Create the machine template scope
+	templateScope, err := scope.NewMachineTemplateScope(scope.MachineTemplateScopeParams{
+		Client:                r.Client,
+		Logger:                log,
+		IBMVPCMachineTemplate: ibmVpcMachineTemplate,
+	}, authenticator, svcEndpoint)
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function, so we can persist any IBMVPCMachineTemplate changes.
+	defer func() {
+		if err := templateScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	if err := templateScope.ReconcileCapacity(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile capacity for IBMVPCMachineTemplate %s/%s", ibmVpcMachineTemplate.Namespace, ibmVpcMachineTemplate.Name)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager creates a new IBMVPCMachineTemplate controller for a manager.
+func (r *IBMVPCMachineTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1alpha3.IBMVPCMachineTemplate{}).
+		Complete(r)
+}
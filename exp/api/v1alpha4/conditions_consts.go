@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+const (
+	// InstanceGroupReadyCondition reports on the state of the instance group backing the machine pool.
+	InstanceGroupReadyCondition clusterv1.ConditionType = "InstanceGroupReady"
+
+	// InstanceGroupNotHealthyReason used when the instance group is not in healthy status.
+	InstanceGroupNotHealthyReason = "InstanceGroupNotHealthy"
+	// InstanceGroupRollingUpdateReason used when instances created from a previous instance template are being replaced.
+	InstanceGroupRollingUpdateReason = "InstanceGroupRollingUpdate"
+)
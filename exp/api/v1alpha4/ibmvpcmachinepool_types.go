@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+)
+
+const (
+	// MachinePoolFinalizer allows IBMVPCMachinePoolReconciler to clean up resources associated with IBMVPCMachinePool before
+	// removing it from the apiserver.
+	MachinePoolFinalizer = "ibmvpcmachinepool.infrastructure.cluster.x-k8s.io"
+)
+
+// IBMVPCMachinePoolSpec defines the desired state of IBMVPCMachinePool
+type IBMVPCMachinePoolSpec struct {
+	// Image is the reference to the OS image which would be install on the instances.
+	Image infrav1.IBMVPCResourceReference `json:"image"`
+
+	// Zone is the place where the instances should be created. Example: us-south-3
+	Zone string `json:"zone"`
+
+	// Profile indicates the flavor of the instances. Example: bx2-8x32
+	Profile string `json:"profile"`
+
+	// SSHKeys is the references to the SSH pub keys that will be used to access the instances.
+	// +optional
+	SSHKeys []*infrav1.IBMVPCResourceReference `json:"sshKeys,omitempty"`
+
+	// Subnet is the reference to the subnet of the primary network interface of the instances.
+	// Defaults to the subnet of the cluster.
+	// +optional
+	Subnet *infrav1.IBMVPCResourceReference `json:"subnet,omitempty"`
+
+	// Tags are the user tags attached to the instances in addition to the cluster tags.
+	// Tags removed from the list are not detached from the instances.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// AccessTags are the access management tags attached to the instances in addition
+	// to the cluster access tags. The tags must already exist in the account. Tags removed from the list
+	// are not detached from the instances.
+	// +optional
+	AccessTags []string `json:"accessTags,omitempty"`
+
+	// ProviderIDList are the identification IDs of the instances provided by the provider.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+}
+
+// IBMVPCMachinePoolStatus defines the observed state of IBMVPCMachinePool
+type IBMVPCMachinePoolStatus struct {
+	// Ready is true when the instance group is provisioned.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the most recently observed number of instances in the instance group.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of healthy instances in the instance group.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// InstanceTemplateID is the id of the instance template the instance group creates instances from.
+	// +optional
+	InstanceTemplateID string `json:"instanceTemplateID,omitempty"`
+
+	// InstanceTemplateName is the name of the instance template, it is suffixed by a hash of the image, zone,
+	// profile, SSH keys and subnet of the pool spec and of the name and value of the bootstrap data secret,
+	// so a new template is rolled out when one of them changes.
+	// +optional
+	InstanceTemplateName string `json:"instanceTemplateName,omitempty"`
+
+	// PreviousInstanceTemplateIDs are the ids of the instance templates replaced by the current one, they are
+	// deleted once neither the instance group nor its instances use them.
+	// +optional
+	PreviousInstanceTemplateIDs []string `json:"previousInstanceTemplateIDs,omitempty"`
+
+	// InstanceGroupID is the id of the instance group backing the pool.
+	// +optional
+	InstanceGroupID string `json:"instanceGroupID,omitempty"`
+
+	// Conditions defines current service state of the IBMVPCMachinePool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ibmvpcmachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool instance group is ready"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of instances in the instance group"
+// +kubebuilder:printcolumn:name="Instance Group",type="string",JSONPath=".status.instanceGroupID",description="ID of the VPC instance group"
+
+// IBMVPCMachinePool is the Schema for the ibmvpcmachinepools API
+type IBMVPCMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IBMVPCMachinePoolSpec   `json:"spec,omitempty"`
+	Status IBMVPCMachinePoolStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the observations of the operational state of the IBMVPCMachinePool resource.
+func (r *IBMVPCMachinePool) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the IBMVPCMachinePool to the predescribed clusterv1.Conditions.
+func (r *IBMVPCMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// IBMVPCMachinePoolList contains a list of IBMVPCMachinePool
+type IBMVPCMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IBMVPCMachinePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IBMVPCMachinePool{}, &IBMVPCMachinePoolList{})
+}
@@ -0,0 +1,160 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1alpha4
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	apiv1alpha4 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
+	cluster_apiapiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachinePool) DeepCopyInto(out *IBMVPCMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachinePool.
+func (in *IBMVPCMachinePool) DeepCopy() *IBMVPCMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMVPCMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachinePoolList) DeepCopyInto(out *IBMVPCMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IBMVPCMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachinePoolList.
+func (in *IBMVPCMachinePoolList) DeepCopy() *IBMVPCMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IBMVPCMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachinePoolSpec) DeepCopyInto(out *IBMVPCMachinePoolSpec) {
+	*out = *in
+	in.Image.DeepCopyInto(&out.Image)
+	if in.SSHKeys != nil {
+		in, out := &in.SSHKeys, &out.SSHKeys
+		*out = make([]*apiv1alpha4.IBMVPCResourceReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(apiv1alpha4.IBMVPCResourceReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(apiv1alpha4.IBMVPCResourceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessTags != nil {
+		in, out := &in.AccessTags, &out.AccessTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachinePoolSpec.
+func (in *IBMVPCMachinePoolSpec) DeepCopy() *IBMVPCMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IBMVPCMachinePoolStatus) DeepCopyInto(out *IBMVPCMachinePoolStatus) {
+	*out = *in
+	if in.PreviousInstanceTemplateIDs != nil {
+		in, out := &in.PreviousInstanceTemplateIDs, &out.PreviousInstanceTemplateIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(cluster_apiapiv1alpha4.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMVPCMachinePoolStatus.
+func (in *IBMVPCMachinePoolStatus) DeepCopy() *IBMVPCMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IBMVPCMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
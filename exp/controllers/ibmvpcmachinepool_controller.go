@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrastructurev1alpha3 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/cloud/scope"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-ibmcloud/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-ibmcloud/pkg"
+)
+
+// IBMVPCMachinePoolReconciler reconciles a IBMVPCMachinePool object
+type IBMVPCMachinePoolReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmvpcmachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ibmvpcmachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+// Reconcile implements controller runtime Reconciler interface and handles reconcileation logic for IBMVPCMachinePool.
+func (r *IBMVPCMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := r.Log.WithValues("ibmvpcmachinepool", req.NamespacedName)
+
+	// Fetch the IBMVPCMachinePool instance.
+	ibmVpcMachinePool := &infrav1exp.IBMVPCMachinePool{}
+	err := r.Get(ctx, req.NamespacedName, ibmVpcMachinePool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the MachinePool.
+	machinePool, err := exputil.GetOwnerMachinePool(ctx, r.Client, ibmVpcMachinePool.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machinePool == nil {
+		log.Info("MachinePool Controller has not yet set OwnerRef")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.Info("MachinePool is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("cluster", cluster.Name)
+
+	ibmCluster := &infrastructurev1alpha3.IBMVPCCluster{}
+	ibmVpcClusterName := client.ObjectKey{
+		Namespace: ibmVpcMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, ibmVpcClusterName, ibmCluster); err != nil {
+		log.Info("IBMVPCCluster is not available yet")
+		return ctrl.Result{}, nil
+	}
+
+	// TODO: Will be removed once we find a better way of overriding the service endpoint, generate via spec
+	svcEndpoint := os.Getenv("SERVICE_ENDPOINT")
+
+	authenticator, err := pkg.GetAuthenticator()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get authenticator")
+	}
+
+	// Create the machine pool scope
+	machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+		Client:            r.Client,
+		Logger:            log,
+		Cluster:           cluster,
+		IBMVPCCluster:     ibmCluster,
+		MachinePool:       machinePool,
+		IBMVPCMachinePool: ibmVpcMachinePool,
+	}, authenticator, svcEndpoint)
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function, so we can persist any IBMVPCMachinePool changes.
+	defer func() {
+		if err := machinePoolScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted machine pools
+	if !ibmVpcMachinePool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(machinePoolScope)
+	}
+
+	// Handle non-deleted machine pools
+	return r.reconcileNormal(machinePoolScope)
+}
+
+// SetupWithManager creates a new IBMVPCMachinePool controller for a manager.
+func (r *IBMVPCMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1exp.IBMVPCMachinePool{}).
+		Watches(
+			&source.Kind{Type: &expclusterv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(exputil.MachinePoolToInfrastructureMapFunc(infrav1exp.GroupVersion.WithKind("IBMVPCMachinePool"), r.Log)),
+		).
+		Complete(r)
+}
+
+func (r *IBMVPCMachinePoolReconciler) reconcileNormal(machinePoolScope *scope.MachinePoolScope) (ctrl.Result, error) {
+	controllerutil.AddFinalizer(machinePoolScope.IBMVPCMachinePool, infrav1exp.MachinePoolFinalizer)
+
+	if !machinePoolScope.Cluster.Status.InfrastructureReady {
+		machinePoolScope.Info("Cluster infrastructure is not ready yet")
+		return ctrl.Result{}, nil
+	}
+
+	// Make sure bootstrap data is available and populated.
+	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		machinePoolScope.Info("Bootstrap data secret reference is not yet available")
+		return ctrl.Result{}, nil
+	}
+
+	if err := machinePoolScope.ReconcileInstanceTemplate(); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile instance template for IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+
+	instanceGroup, err := machinePoolScope.ReconcileInstanceGroup()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile instance group for IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+
+	memberships, err := machinePoolScope.GetMemberships()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list instance group memberships for IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+
+	if err := machinePoolScope.DeletePreviousInstanceTemplates(instanceGroup, memberships); err != nil {
+		machinePoolScope.Error(err, "failed to delete previous instance templates")
+	}
+
+	providerIDList := []string{}
+	readyReplicas := int32(0)
+	for _, membership := range memberships {
+		if membership.Instance == nil {
+			continue
+		}
+		providerIDList = append(providerIDList, machinePoolScope.ProviderID(membership.Instance))
+		if *membership.Status == vpcv1.InstanceGroupMembershipStatusHealthyConst {
+			readyReplicas++
+		}
+	}
+	machinePoolScope.IBMVPCMachinePool.Spec.ProviderIDList = providerIDList
+	machinePoolScope.IBMVPCMachinePool.Status.Replicas = int32(len(memberships))
+	machinePoolScope.IBMVPCMachinePool.Status.ReadyReplicas = readyReplicas
+	machinePoolScope.IBMVPCMachinePool.Status.Ready = true
+
+	if err := machinePoolScope.ReconcileTags(memberships); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile tags for IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+
+	if *instanceGroup.Status != vpcv1.InstanceGroupStatusHealthyConst {
+		conditions.MarkFalse(machinePoolScope.IBMVPCMachinePool, infrav1exp.InstanceGroupReadyCondition, infrav1exp.InstanceGroupNotHealthyReason, clusterv1.ConditionSeverityWarning,
+			"instance group %s is in %s status", *instanceGroup.Name, *instanceGroup.Status)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	rollingUpdate, err := machinePoolScope.RollOutInstanceTemplate(memberships)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to roll out instance template for IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+	if rollingUpdate {
+		conditions.MarkFalse(machinePoolScope.IBMVPCMachinePool, infrav1exp.InstanceGroupReadyCondition, infrav1exp.InstanceGroupRollingUpdateReason, clusterv1.ConditionSeverityInfo,
+			"replacing instances created from previous instance templates")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	conditions.MarkTrue(machinePoolScope.IBMVPCMachinePool, infrav1exp.InstanceGroupReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+func (r *IBMVPCMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope) (ctrl.Result, error) {
+	machinePoolScope.Info("Handling deleted IBMVPCMachinePool")
+
+	deleted, err := machinePoolScope.DeleteInstanceGroup()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "error deleting instance group of IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+	if !deleted {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if err := machinePoolScope.DeletePreviousInstanceTemplates(nil, nil); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "error deleting previous instance templates of IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+	}
+	if templateID := machinePoolScope.IBMVPCMachinePool.Status.InstanceTemplateID; templateID != "" {
+		if err := machinePoolScope.DeleteInstanceTemplate(templateID); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "error deleting instance template of IBMVPCMachinePool %s/%s", machinePoolScope.IBMVPCMachinePool.Namespace, machinePoolScope.IBMVPCMachinePool.Name)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(machinePoolScope.IBMVPCMachinePool, infrav1exp.MachinePoolFinalizer)
+	return ctrl.Result{}, nil
+}
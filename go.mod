@@ -8,11 +8,14 @@ require (
 	github.com/IBM/go-sdk-core/v5 v5.9.0
 	github.com/IBM/vpc-go-sdk v0.14.0
 	github.com/go-logr/logr v0.4.0
+	github.com/go-openapi/runtime v0.21.0
+	github.com/go-openapi/strfmt v0.21.1
 	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/onsi/ginkgo v1.16.5
 	github.com/onsi/gomega v1.17.0
 	github.com/pkg/errors v0.9.1
 	github.com/ppc64le-cloud/powervs-utils v0.0.0-20210106101518-5d3f965b0344
+	github.com/spf13/pflag v1.0.5
 	k8s.io/api v0.21.7
 	k8s.io/apimachinery v0.21.7
 	k8s.io/client-go v0.21.7
@@ -21,16 +21,22 @@ import (
 	"os"
 	"time"
 
+	"github.com/spf13/pflag"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	infrastructurev1alpha3 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha3"
 	infrastructurev1alpha4 "sigs.k8s.io/cluster-api-provider-ibmcloud/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-ibmcloud/controllers"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-ibmcloud/exp/api/v1alpha4"
+	expcontrollers "sigs.k8s.io/cluster-api-provider-ibmcloud/exp/controllers"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -44,7 +50,9 @@ func init() {
 
 	_ = infrastructurev1alpha3.AddToScheme(scheme)
 	_ = infrastructurev1alpha4.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = expclusterv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -55,7 +63,9 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.Parse()
+	feature.MutableGates.AddFlag(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 	syncPeriod := 15 * time.Second
@@ -88,6 +98,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "IBMVPCMachine")
 		os.Exit(1)
 	}
+	if err = (&controllers.IBMVPCMachineTemplateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("IBMVPCMachineTemplate"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IBMVPCMachineTemplate")
+		os.Exit(1)
+	}
 	if err = (&controllers.IBMPowerVSClusterReconciler{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("IBMPowerVSCluster"),
@@ -97,13 +115,32 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.IBMPowerVSMachineReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("IBMPowerVSMachine"),
+		Scheme:    mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IBMPowerVSMachine")
+		os.Exit(1)
+	}
+	if err = (&controllers.IBMPowerVSImageReconciler{
 		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("IBMPowerVSMachine"),
+		Log:    ctrl.Log.WithName("controllers").WithName("IBMPowerVSImage"),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "IBMPowerVSMachine")
+		setupLog.Error(err, "unable to create controller", "controller", "IBMPowerVSImage")
 		os.Exit(1)
 	}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err = (&expcontrollers.IBMVPCMachinePoolReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("IBMVPCMachinePool"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IBMVPCMachinePool")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -21,23 +21,29 @@ import (
 	"strings"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
 
 	"github.com/IBM-Cloud/bluemix-go"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev1/catalog"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev1/controller"
 	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/controllerv2"
+	"github.com/IBM-Cloud/bluemix-go/api/resource/resourcev2/managementv2"
 	"github.com/IBM-Cloud/bluemix-go/authentication"
 	"github.com/IBM-Cloud/bluemix-go/http"
 	"github.com/IBM-Cloud/bluemix-go/rest"
 	bxsession "github.com/IBM-Cloud/bluemix-go/session"
 	"github.com/IBM/go-sdk-core/v5/core"
-
-	"k8s.io/klog/v2"
 )
 
 // Client is used to communicate with IBM Cloud holds the session and user information
 type Client struct {
 	*bxsession.Session
-	User           *User
-	ResourceClient controllerv2.ResourceServiceInstanceRepository
+	User                *User
+	ResourceClient      controllerv2.ResourceServiceInstanceRepository
+	ResourceGroupClient managementv2.ResourceGroupRepository
+	// ResourceControllerClient creates and deletes service instances, which the v2 API does not support.
+	ResourceControllerClient controller.ResourceServiceInstanceRepository
+	ResourceCatalogClient    catalog.ResourceCatalogRepository
 }
 
 func authenticateAPIKey(sess *bxsession.Session) error {
@@ -100,40 +106,60 @@ func fetchUserDetails(sess *bxsession.Session, generation int) (*User, error) {
 }
 
 // NewClient instantiates and returns an IBM Cloud Client object with session, resource controller and userDetails
-func NewClient() *Client {
+func NewClient() (*Client, error) {
 	c := &Client{}
 
 	authenticator, err := GetAuthenticator()
 	if err != nil {
-		klog.Fatal(err)
+		return nil, err
 	}
 	//TODO: this will be removed once power-go-client migrated to go-sdk-core
 	auth, ok := authenticator.(*core.IamAuthenticator)
 	if !ok {
-		klog.Fatal("failed to assert the authenticator as IAM type, please check the ibm-credentials.env file")
+		return nil, errors.New("failed to assert the authenticator as IAM type, please check the ibm-credentials.env file")
 	}
 	bxSess, err := bxsession.New(&bluemix.Config{BluemixAPIKey: auth.ApiKey})
 	if err != nil {
-		klog.Fatal(err)
+		return nil, err
 	}
 
 	c.Session = bxSess
 
-	err = authenticateAPIKey(bxSess)
-	if err != nil {
-		klog.Fatal(err)
+	if err := authenticateAPIKey(bxSess); err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate the API key")
 	}
 
 	c.User, err = fetchUserDetails(bxSess, 2)
 	if err != nil {
-		klog.Fatal(err)
+		return nil, errors.Wrap(err, "failed to fetch the user details")
 	}
 
 	ctrlv2, err := controllerv2.New(bxSess)
 	if err != nil {
-		klog.Fatal(err)
+		return nil, err
 	}
 
 	c.ResourceClient = ctrlv2.ResourceServiceInstanceV2()
-	return c
+
+	ctrlv1, err := controller.New(bxSess)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ResourceControllerClient = ctrlv1.ResourceServiceInstance()
+
+	catalogAPI, err := catalog.New(bxSess)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ResourceCatalogClient = catalogAPI.ResourceCatalog()
+
+	mgmtv2, err := managementv2.New(bxSess)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ResourceGroupClient = mgmtv2.ResourceGroup()
+	return c, nil
 }
@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cosSigningAlgorithm = "AWS4-HMAC-SHA256"
+	cosDateFormat       = "20060102T150405Z"
+	cosUnsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// COSClient is a minimal client of the S3 compatible API of IBM Cloud Object Storage authenticated with HMAC credentials.
+// Buckets and objects are addressed path-style, <endpoint>/<bucket>/<key>.
+type COSClient struct {
+	endpoint        *url.URL
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+	now             func() time.Time
+}
+
+// NewCOSClient instantiates a Cloud Object Storage client for the endpoint and region with the HMAC credentials.
+func NewCOSClient(endpoint, region, accessKeyID, secretAccessKey string) (*COSClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Cloud Object Storage endpoint %q", endpoint)
+	}
+	return &COSClient{
+		endpoint:        u,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		httpClient:      http.DefaultClient,
+		now:             time.Now,
+	}, nil
+}
+
+// EnsureBucket creates the bucket if it does not exist, it returns whether the bucket was created.
+func (c *COSClient) EnsureBucket(bucket string) (bool, error) {
+	response, err := c.do(http.MethodHead, bucket, "", nil)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode == http.StatusOK {
+		return false, nil
+	} else if response.StatusCode != http.StatusNotFound {
+		return false, fmt.Errorf("failed to get bucket %s: %s", bucket, response.Status)
+	}
+
+	response, err = c.do(http.MethodPut, bucket, "", nil)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to create bucket %s: %s", bucket, response.Status)
+	}
+	return true, nil
+}
+
+// DeleteBucket deletes the bucket, which must be empty. Deleting a bucket that does not exist is not an error.
+func (c *COSClient) DeleteBucket(bucket string) error {
+	response, err := c.do(http.MethodDelete, bucket, "", nil)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete bucket %s: %s", bucket, response.Status)
+	}
+	return nil
+}
+
+// PutObject uploads the data to the object with the given key.
+func (c *COSClient) PutObject(bucket, key string, data []byte) error {
+	response, err := c.do(http.MethodPut, bucket, key, data)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put object %s/%s: %s", bucket, key, response.Status)
+	}
+	return nil
+}
+
+// DeleteObject deletes the object with the given key, deleting an object that does not exist is not an error.
+func (c *COSClient) DeleteObject(bucket, key string) error {
+	response, err := c.do(http.MethodDelete, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete object %s/%s: %s", bucket, key, response.Status)
+	}
+	return nil
+}
+
+// PresignGetObject returns a URL that downloads the object without credentials until it expires.
+func (c *COSClient) PresignGetObject(bucket, key string, expires time.Duration) string {
+	return c.presign(http.MethodGet, c.objectURL(bucket, key), expires)
+}
+
+func (c *COSClient) objectURL(bucket, key string) *url.URL {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = ""
+	u.RawQuery = ""
+	return &u
+}
+
+func (c *COSClient) do(method, bucket, key string, body []byte) (*http.Response, error) {
+	u := c.objectURL(bucket, key)
+	request, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	c.sign(request, body)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	return response, nil
+}
+
+// sign adds the signature version 4 authorization header to the request.
+func (c *COSClient) sign(request *http.Request, body []byte) {
+	now := c.now().UTC()
+	payloadHash := sha256Hex(body)
+	request.Header.Set("X-Amz-Date", now.Format(cosDateFormat))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 request.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           now.Format(cosDateFormat),
+	}
+	signedHeaders, canonicalHeaders := canonicalHeaders(headers)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI(request.URL),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := c.credentialScope(now)
+	signature := c.signature(now, scope, canonicalRequest)
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cosSigningAlgorithm, c.accessKeyID, scope, signedHeaders, signature))
+}
+
+// presign returns the URL with the signature version 4 query parameters, only the host header is signed.
+func (c *COSClient) presign(method string, u *url.URL, expires time.Duration) string {
+	now := c.now().UTC()
+	scope := c.credentialScope(now)
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", cosSigningAlgorithm)
+	query.Set("X-Amz-Credential", c.accessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(cosDateFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	signedHeaders, canonicalHeaders := canonicalHeaders(map[string]string{"host": u.Host})
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(query),
+		canonicalHeaders,
+		signedHeaders,
+		cosUnsignedPayload,
+	}, "\n")
+
+	signed := *u
+	signed.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + c.signature(now, scope, canonicalRequest)
+	return signed.String()
+}
+
+func (c *COSClient) credentialScope(t time.Time) string {
+	return strings.Join([]string{t.Format("20060102"), c.region, "s3", "aws4_request"}, "/")
+}
+
+func (c *COSClient) signature(t time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		cosSigningAlgorithm,
+		t.Format(cosDateFormat),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), t.Format("20060102"))
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func canonicalURI(u *url.URL) string {
+	path := u.Path
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var params []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters, as required by signature version 4.
+func uriEncode(s string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
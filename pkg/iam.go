@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"net/http"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// IAMIdentityURL is the default endpoint of the IBM Cloud IAM Identity API.
+const IAMIdentityURL = "https://iam.cloud.ibm.com"
+
+// IAMIdentityClient is used to look up IAM trusted profiles.
+type IAMIdentityClient struct {
+	service *core.BaseService
+}
+
+// TrustedProfile is an IAM trusted profile.
+type TrustedProfile struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CRN       string `json:"crn"`
+	AccountID string `json:"account_id"`
+}
+
+// NewIAMIdentityClient instantiates an IAM Identity client, svcEndpoint defaults to IAMIdentityURL when empty.
+func NewIAMIdentityClient(authenticator core.Authenticator, svcEndpoint string) (*IAMIdentityClient, error) {
+	if svcEndpoint == "" {
+		svcEndpoint = IAMIdentityURL
+	}
+	service, err := core.NewBaseService(&core.ServiceOptions{
+		URL:           svcEndpoint,
+		Authenticator: authenticator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &IAMIdentityClient{service: service}, nil
+}
+
+// GetTrustedProfile returns the trusted profile with the given ID, or nil if the profile does not exist.
+func (c *IAMIdentityClient) GetTrustedProfile(id string) (*TrustedProfile, error) {
+	builder := core.NewRequestBuilder(core.GET)
+	if _, err := builder.ResolveRequestURL(c.service.GetServiceURL(), "/v1/profiles/{profile-id}", map[string]string{"profile-id": id}); err != nil {
+		return nil, err
+	}
+	builder.AddHeader("Accept", "application/json")
+	request, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &TrustedProfile{}
+	response, err := c.service.Request(request, profile)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
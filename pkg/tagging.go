@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+const (
+	// GlobalTaggingURL is the default endpoint of the IBM Cloud Global Tagging API.
+	GlobalTaggingURL = "https://tags.global-search-tagging.cloud.ibm.com"
+
+	// TagTypeUser is the type of the user tags.
+	TagTypeUser = "user"
+	// TagTypeAccess is the type of the access management tags.
+	TagTypeAccess = "access"
+)
+
+// GlobalTaggingClient is used to attach tags to IBM Cloud resources identified by their CRN.
+type GlobalTaggingClient struct {
+	service *core.BaseService
+}
+
+type tagList struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+}
+
+type tagResults struct {
+	Results []struct {
+		ResourceID string `json:"resource_id"`
+		IsError    bool   `json:"is_error"`
+	} `json:"results"`
+}
+
+// NewGlobalTaggingClient instantiates a Global Tagging client, svcEndpoint defaults to GlobalTaggingURL when empty.
+func NewGlobalTaggingClient(authenticator core.Authenticator, svcEndpoint string) (*GlobalTaggingClient, error) {
+	if svcEndpoint == "" {
+		svcEndpoint = GlobalTaggingURL
+	}
+	service, err := core.NewBaseService(&core.ServiceOptions{
+		URL:           svcEndpoint,
+		Authenticator: authenticator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GlobalTaggingClient{service: service}, nil
+}
+
+// GetTags returns the names of the tags of the given type attached to the resource.
+func (c *GlobalTaggingClient) GetTags(crn, tagType string) ([]string, error) {
+	builder := core.NewRequestBuilder(core.GET)
+	if _, err := builder.ResolveRequestURL(c.service.GetServiceURL(), "/v3/tags", nil); err != nil {
+		return nil, err
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddQuery("attached_to", crn)
+	builder.AddQuery("tag_type", tagType)
+	builder.AddQuery("limit", "1000")
+	request, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &tagList{}
+	if _, err := c.service.Request(request, result); err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
+		tags = append(tags, item.Name)
+	}
+	return tags, nil
+}
+
+// AttachTags attaches the tags of the given type to the resource.
+func (c *GlobalTaggingClient) AttachTags(crn string, tags []string, tagType string) error {
+	builder := core.NewRequestBuilder(core.POST)
+	if _, err := builder.ResolveRequestURL(c.service.GetServiceURL(), "/v3/tags/attach", nil); err != nil {
+		return err
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddHeader("Content-Type", "application/json")
+	builder.AddQuery("tag_type", tagType)
+	body := map[string]interface{}{
+		"resources": []map[string]string{
+			{"resource_id": crn},
+		},
+		"tag_names": tags,
+	}
+	if _, err := builder.SetBodyContentJSON(body); err != nil {
+		return err
+	}
+	request, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	result := &tagResults{}
+	if _, err := c.service.Request(request, result); err != nil {
+		return err
+	}
+	for _, r := range result.Results {
+		if r.IsError {
+			return fmt.Errorf("failed to attach %s tags to resource %s", tagType, r.ResourceID)
+		}
+	}
+	return nil
+}